@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day08"
+)
+
+func runAntinodes(args []string) error {
+	flags := flag.NewFlagSet("antinodes", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(8), "day 8 style antenna map to search")
+	step := flags.String("step", string(day08.StepRaw), "step along each line by the raw difference between the antennas, or by it reduced by its gcd (raw or reduced)")
+	ratios := flags.String("ratios", "2", "comma separated distance ratios an antinode must be at, or empty for every point on the line")
+	cells := flags.Bool("cells", false, "list the antinode cells of each frequency")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	opts := day08.Options{Step: day08.StepMode(*step)}
+	if strings.TrimSpace(*ratios) != "" {
+		for _, field := range strings.Split(*ratios, ",") {
+			ratio, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return fmt.Errorf("--ratios must be a comma separated list of numbers, got %q", *ratios)
+			}
+			opts.Rule.Ratios = append(opts.Rule.Ratios, ratio)
+		}
+	}
+
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+	groups, err := day08.Antinodes(string(input), opts)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if *cells {
+		fmt.Fprintln(w, "FREQUENCY\tANTENNAS\tANTINODES\tCELLS")
+	} else {
+		fmt.Fprintln(w, "FREQUENCY\tANTENNAS\tANTINODES")
+	}
+	for _, group := range groups {
+		fmt.Fprintf(w, "%s\t%d\t%d", group.Frequency, len(group.Antennas), len(group.Antinodes))
+		if *cells {
+			listed := make([]string, len(group.Antinodes))
+			for i, cell := range group.Antinodes {
+				listed[i] = fmt.Sprintf("%d,%d", cell.Row, cell.Col)
+			}
+			fmt.Fprintf(w, "\t%s", strings.Join(listed, " "))
+		}
+		fmt.Fprintln(w)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d distinct antinode(s)\n", len(day08.Distinct(groups)))
+	return nil
+}
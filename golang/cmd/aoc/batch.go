@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sourcegraph/conc/pool"
+
+	"advent_of_code_2024/internal/days"
+	"advent_of_code_2024/internal/solver"
+)
+
+const answersFileName = "answers.json"
+
+// expectedAnswers is one input's entry in answers.json, keyed by input file
+// name, e.g. {"alice": {"part1": "11", "part2": "31"}}. Either part may be
+// left out to skip checking it.
+type expectedAnswers struct {
+	PartOne string `json:"part1,omitempty"`
+	PartTwo string `json:"part2,omitempty"`
+}
+
+func (ea expectedAnswers) forPart(part int) string {
+	if part == 1 {
+		return ea.PartOne
+	}
+	return ea.PartTwo
+}
+
+type batchResult struct {
+	input    string
+	part     int
+	answer   string
+	duration time.Duration
+	err      error
+}
+
+func runBatch(args []string) error {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	day := flags.Int("day", 0, "day to solve the inputs for (1-25)")
+	dir := flags.String("dir", "", "directory of input files to solve")
+	workers := flags.Int("workers", runtime.NumCPU(), "maximum number of parts to solve at once")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	s, ok := days.Get(*day)
+	if !ok {
+		return fmt.Errorf("no solver for day %d", *day)
+	}
+	if *dir == "" {
+		return errors.New("--dir is required")
+	}
+	if *workers < 1 {
+		return fmt.Errorf("--workers must be at least 1, got %d", *workers)
+	}
+
+	inputNames, err := listInputs(*dir)
+	if err != nil {
+		return err
+	}
+	expected, err := readExpectedAnswers(*dir)
+	if err != nil {
+		return err
+	}
+
+	// Every input is read before any solving starts, so that a read error
+	// can return straight away without leaving solves running.
+	inputs := make([][]byte, len(inputNames))
+	for i, name := range inputNames {
+		if inputs[i], err = os.ReadFile(filepath.Join(*dir, name)); err != nil {
+			return err
+		}
+	}
+
+	results := make([]batchResult, len(inputNames)*2)
+	p := pool.New().WithMaxGoroutines(*workers)
+	for i, name := range inputNames {
+		input := inputs[i]
+		for part := 1; part <= 2; part++ {
+			result := &results[i*2+part-1]
+			p.Go(func() {
+				start := time.Now()
+				answer, err := solver.Solve(s, part, string(input))
+				*result = batchResult{
+					input:    name,
+					part:     part,
+					answer:   answer,
+					duration: time.Since(start),
+					err:      err,
+				}
+			})
+		}
+	}
+	p.Wait()
+
+	failures := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INPUT\tPART\tANSWER\tDURATION\tCHECK\tERROR")
+	for _, r := range results {
+		if errors.Is(r.err, solver.ErrNoPart) {
+			continue
+		}
+		check := "-"
+		if want := expected[r.input].forPart(r.part); want != "" && r.err == nil {
+			if r.answer == want {
+				check = "ok"
+			} else {
+				check = fmt.Sprintf("want %s", want)
+				failures += 1
+			}
+		}
+		errText := ""
+		if r.err != nil {
+			errText = r.err.Error()
+			failures += 1
+		}
+		fmt.Fprintf(
+			w,
+			"%s\t%d\t%s\t%v\t%s\t%s\n",
+			r.input,
+			r.part,
+			r.answer,
+			r.duration.Round(time.Microsecond),
+			check,
+			errText,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d part(s) failed or did not match %s", failures, answersFileName)
+	}
+	return nil
+}
+
+// listInputs returns the names of the input files in dir, skipping
+// answers.json and hidden files.
+func listInputs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if entry.Name() == answersFileName || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no input files found in %s", dir)
+	}
+	return names, nil
+}
+
+// readExpectedAnswers reads answers.json from dir. It is optional, so a missing
+// file gives no expected answers rather than an error.
+func readExpectedAnswers(dir string) (map[string]expectedAnswers, error) {
+	data, err := os.ReadFile(filepath.Join(dir, answersFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]expectedAnswers{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expected := make(map[string]expectedAnswers)
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", answersFileName, err)
+	}
+	return expected, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day07"
+)
+
+func runCalibrate(args []string) error {
+	flags := flag.NewFlagSet("calibrate", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(7), "day 7 style calibration equations")
+	operators := flags.String(
+		"operators",
+		"+,*",
+		"operator sets to solve with, separated by ';' and optionally named as name=ops, each a comma separated list from +, -, *, /, || and ||N for concatenation in base N",
+	)
+	bigNumbers := flags.Bool("big", false, "work with exact big integers, for numbers that don't fit in 64 bits")
+	format := flags.String("format", "text", "output format: text, csv or json")
+	workers := flags.Int("workers", 0, "equations to solve at once (defaults to one per CPU)")
+	all := flags.Bool("all", false, "include equations no operator set can solve in text output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown --format %q, expected text, csv or json", *format)
+	}
+	if *workers < 0 {
+		return fmt.Errorf("--workers must not be negative, got %d", *workers)
+	}
+	sets, err := day07.ParseOperatorSets(*operators)
+	if err != nil {
+		return err
+	}
+
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+	opts := day07.CheckOptions{Sets: sets, Workers: *workers}
+	if *bigNumbers {
+		results, err := day07.CheckBig(string(input), opts)
+		if err != nil {
+			return err
+		}
+		return printEquationResults(results, sets, *format, *all)
+	}
+	results, err := day07.Check(string(input), opts)
+	if err != nil {
+		return err
+	}
+	return printEquationResults(results, sets, *format, *all)
+}
+
+func printEquationResults[T int | *big.Int](results []day07.EquationResult[T], sets []day07.OperatorSet, format string, all bool) error {
+	switch format {
+	case "csv":
+		return day07.WriteCSV(os.Stdout, sets, results)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := []string{"EQUATION", "TARGET"}
+	for _, set := range sets {
+		header = append(header, strings.ToUpper(set.Name))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	solvable := make([]int, len(sets))
+	for _, result := range results {
+		row := []string{fmt.Sprint(result.Index), fmt.Sprint(result.Target)}
+		anySolvable := false
+		for i, r := range result.Results {
+			expression := "no solution"
+			if r.Solvable {
+				anySolvable = true
+				solvable[i] += 1
+				expression = r.Expression
+			}
+			row = append(row, expression)
+		}
+		if anySolvable || all {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for i, set := range sets {
+		fmt.Printf("%s: %d of %d equation(s) solvable\n", set.Name, solvable[i], len(results))
+	}
+	return nil
+}
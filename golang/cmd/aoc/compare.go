@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day01"
+)
+
+func runCompare(args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(1), "file with one list per column")
+	formatName := flags.String("format", "whitespace", "column layout: whitespace, csv or tsv")
+	header := flags.Bool("header", false, "treat the first row as column names")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	format, err := day01.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(*inputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	lists, err := day01.LoadLists(file, day01.LoadOptions{Format: format, Header: *header})
+	if err != nil {
+		return fmt.Errorf("%s: %w", *inputPath, err)
+	}
+
+	distances, err := day01.DistanceMatrix(lists)
+	if err != nil {
+		return err
+	}
+	similarities, err := day01.SimilarityMatrix(lists)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printMatrix(w, "DISTANCE", lists.Names, distances)
+	fmt.Fprintln(w)
+	printMatrix(w, "SIMILARITY", lists.Names, similarities)
+	return w.Flush()
+}
+
+func printMatrix(w *tabwriter.Writer, title string, names []string, matrix [][]int) {
+	fmt.Fprintf(w, "%s\t%s\n", title, strings.Join(names, "\t"))
+	for i, row := range matrix {
+		fmt.Fprint(w, names[i])
+		for _, value := range row {
+			fmt.Fprintf(w, "\t%d", value)
+		}
+		fmt.Fprintln(w)
+	}
+}
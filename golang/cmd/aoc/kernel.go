@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day04"
+)
+
+func runKernel(args []string) error {
+	flags := flag.NewFlagSet("kernel", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(4), "grid of letters to search")
+	pattern := flags.String("pattern", "M.S/.A./M.S", "kernel to match, rows separated by '/', with '.' for any letter and [AB] for either")
+	render := flags.String("render", "", "draw the matches instead of listing them: text, color or html")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	switch *render {
+	case "", "text", "color", "html":
+	default:
+		return fmt.Errorf("unknown --render %q, expected text, color or html", *render)
+	}
+
+	kernel, err := day04.ParseKernel(*pattern)
+	if err != nil {
+		return err
+	}
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+	grid, err := day04.ParseGrid(string(input))
+	if err != nil {
+		return fmt.Errorf("%s: %w", *inputPath, err)
+	}
+	matches, err := day04.MatchKernel(grid, kernel)
+	if err != nil {
+		return err
+	}
+
+	switch *render {
+	case "text", "color":
+		fmt.Print(day04.Render(grid, day04.KernelCells(kernel, matches), day04.RenderOptions{Color: *render == "color"}))
+		return nil
+	case "html":
+		fmt.Print(day04.RenderHTML(grid, day04.KernelCells(kernel, matches)))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tCOL\tTRANSFORM")
+	for _, m := range matches {
+		fmt.Fprintf(w, "%d\t%d\t%s\n", m.Row, m.Col, m.Transform)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d match(es)\n", len(matches))
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"advent_of_code_2024/internal/lint"
+)
+
+// defaultInputPath is where each day's puzzle input lives, relative to the
+// module root.
+func defaultInputPath(day int) string {
+	return fmt.Sprintf("cmd/day%02d/input", day)
+}
+
+func runLint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	day := flags.Int("day", 0, "day to lint the input for (1-25)")
+	inputPath := flags.String("input", "", "input file to lint (defaults to the day's embedded input)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *day < 1 || *day > 25 {
+		return fmt.Errorf("--day must be between 1 and 25, got %d", *day)
+	}
+	if *inputPath == "" {
+		*inputPath = defaultInputPath(*day)
+	}
+
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+
+	violations, err := lint.Lint(*day, string(input))
+	if err != nil {
+		return err
+	}
+	for _, v := range violations {
+		fmt.Printf("%s:%d: %s\n", *inputPath, v.Line, v.Message)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d violation(s) found", len(violations))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day06"
+)
+
+func runLoops(args []string) error {
+	flags := flag.NewFlagSet("loops", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(6), "day 6 style floor plan to search")
+	render := flags.Bool("render", false, "draw each loop on the floor plan")
+	obstruction := flags.String("obstruction", "", "only show the loop for the obstruction at row,col")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+	loops, err := day06.FindLoops(string(input))
+	if err != nil {
+		return err
+	}
+	if *obstruction != "" {
+		var cell day06.Cell
+		if _, err := fmt.Sscanf(*obstruction, "%d,%d", &cell.Row, &cell.Col); err != nil {
+			return fmt.Errorf("--obstruction must be row,col, got %q", *obstruction)
+		}
+		selected := make([]day06.Loop, 0, 1)
+		for _, loop := range loops {
+			if loop.Obstruction == cell {
+				selected = append(selected, loop)
+			}
+		}
+		if len(selected) == 0 {
+			return fmt.Errorf("an obstruction at %d,%d doesn't cause a loop", cell.Row, cell.Col)
+		}
+		loops = selected
+	}
+
+	if *render {
+		for _, loop := range loops {
+			rendered, err := day06.RenderLoop(string(input), loop)
+			if err != nil {
+				return err
+			}
+			if loop.Trapped {
+				fmt.Printf("obstruction at %d,%d traps the guard:\n", loop.Obstruction.Row, loop.Obstruction.Col)
+			} else {
+				fmt.Printf("obstruction at %d,%d, loop of %d step(s):\n", loop.Obstruction.Row, loop.Obstruction.Col, loop.Len())
+			}
+			fmt.Println(rendered)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tCOL\tLENGTH\tTURNS")
+	trapped := 0
+	for _, loop := range loops {
+		turns := make([]string, len(loop.Turns))
+		for i, turn := range loop.Turns {
+			turns[i] = fmt.Sprintf("%d,%d%s", turn.Row, turn.Col, turn.Facing)
+		}
+		if loop.Trapped {
+			trapped += 1
+			turns = []string{"trapped"}
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%s\n", loop.Obstruction.Row, loop.Obstruction.Col, loop.Len(), strings.Join(turns, " "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d looping obstruction(s), %d of them trapping the guard\n", len(loops), trapped)
+	return nil
+}
@@ -0,0 +1,64 @@
+// Command aoc holds tooling that works across every day, such as checking
+// inputs before solving, solving many inputs at once, or serving the solvers
+// over HTTP.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{name: "lint", summary: "validate a day's input format before solving", run: runLint},
+	{name: "batch", summary: "solve a directory of inputs for a day and check the answers", run: runBatch},
+	{name: "reconcile", summary: "pair up day 1 lists too large for memory using an external sort", run: runReconcile},
+	{name: "compare", summary: "compare any number of day 1 style lists column by column", run: runCompare},
+	{name: "safety", summary: "list the day 2 reports that break a rule set and why", run: runSafety},
+	{name: "scrape", summary: "run day 3 style instructions found in noisy text", run: runScrape},
+	{name: "wordsearch", summary: "find words in a day 4 style grid in all eight directions", run: runWordSearch},
+	{name: "kernel", summary: "match a 2D pattern against a day 4 style grid in every orientation", run: runKernel},
+	{name: "orderings", summary: "explain why day 5 updates are out of order and how to fix them", run: runOrderings},
+	{name: "loops", summary: "list the day 6 obstructions that trap the guard and draw its loops", run: runLoops},
+	{name: "patrol", summary: "walk several day 6 guards with their own rules and report coverage and collisions", run: runPatrol},
+	{name: "calibrate", summary: "show the operators that solve each day 7 equation", run: runCalibrate},
+	{name: "antinodes", summary: "show the antinodes of each day 8 frequency", run: runAntinodes},
+	{name: "new", summary: "generate the solver, test and input files for a new day", run: runNew},
+	{name: "serve", summary: "serve the solvers over HTTP as a JSON API", run: runServe},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: aoc <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-11s %s\n", c.name, c.summary)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+		if err := c.run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "aoc %s: %v\n", c.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "aoc: unknown command %q\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// scaffold is the data every template is executed with.
+type scaffold struct {
+	Module  string
+	Day     int
+	Package string
+	Title   string
+}
+
+// generatedFile is one file written by aoc new. Files without a template are
+// created empty for the puzzle text to be pasted into.
+type generatedFile struct {
+	path     string
+	template string
+}
+
+func runNew(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	day := flags.Int("day", 0, "day to create (1-25)")
+	title := flags.String("title", "", "puzzle title for the package comment, e.g. \"Historian Hysteria\"")
+	root := flags.String("root", ".", "module root to create the day in")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *day < 1 || *day > 25 {
+		return fmt.Errorf("--day must be between 1 and 25, got %d", *day)
+	}
+
+	module, err := readModulePath(*root)
+	if err != nil {
+		return err
+	}
+	data := scaffold{
+		Module:  module,
+		Day:     *day,
+		Package: fmt.Sprintf("day%02d", *day),
+		Title:   *title,
+	}
+
+	dayDir := filepath.Join(*root, "internal", data.Package)
+	if _, err := os.Stat(dayDir); err == nil {
+		return fmt.Errorf("%s already exists", dayDir)
+	}
+
+	files := []generatedFile{
+		{path: filepath.Join("internal", data.Package, data.Package+".go"), template: "day.go.tmpl"},
+		{path: filepath.Join("internal", data.Package, data.Package+"_test.go"), template: "day_test.go.tmpl"},
+		{path: filepath.Join("internal", data.Package, "testdata", "example")},
+		{path: filepath.Join("cmd", data.Package, "main.go"), template: "main.go.tmpl"},
+		{path: filepath.Join("cmd", data.Package, "input")},
+	}
+	for _, f := range files {
+		if err := writeGenerated(filepath.Join(*root, f.path), f.template, data); err != nil {
+			return err
+		}
+		fmt.Println("created", f.path)
+	}
+
+	if err := registerDay(*root, data); err != nil {
+		return fmt.Errorf("registering %s: %w", data.Package, err)
+	}
+	fmt.Println("registered", data.Package, "in internal/days")
+	fmt.Printf("add a schema for day %d to internal/lint/schemas.go to lint its inputs\n", data.Day)
+	return nil
+}
+
+// readModulePath returns the module path declared in root's go.mod, which the
+// generated imports are relative to.
+func readModulePath(root string) (string, error) {
+	goMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("%s is not a module root: %w", root, err)
+	}
+	for _, line := range strings.Split(string(goMod), "\n") {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.TrimSpace(module), nil
+		}
+	}
+	return "", fmt.Errorf("no module directive in %s", filepath.Join(root, "go.mod"))
+}
+
+func writeGenerated(path string, templateName string, data scaffold) error {
+	var content []byte
+	if templateName != "" {
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, templateName, data); err != nil {
+			return err
+		}
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("formatting %s: %w", path, err)
+		}
+		content = formatted
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// registerDay adds the new day's import and map entry to internal/days, so
+// that lint, batch and serve pick it up straight away.
+func registerDay(root string, data scaffold) error {
+	path := filepath.Join(root, "internal", "days", "days.go")
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := string(source)
+
+	solverImport := fmt.Sprintf("\t%q\n", data.Module+"/internal/solver")
+	if !strings.Contains(text, solverImport) {
+		return fmt.Errorf("no solver import in %s", path)
+	}
+	dayImport := fmt.Sprintf("\t%q\n", data.Module+"/internal/"+data.Package)
+	text = strings.Replace(text, solverImport, dayImport+solverImport, 1)
+
+	mapStart := strings.Index(text, "var solvers = map[int]solver.Solver{\n")
+	if mapStart == -1 {
+		return fmt.Errorf("no solvers map in %s", path)
+	}
+	// Keep the map in day order by inserting before the first later day.
+	insertAt := mapStart + strings.Index(text[mapStart:], "\n}\n") + 1
+	for offset := mapStart; offset < insertAt; {
+		lineEnd := offset + strings.Index(text[offset:], "\n") + 1
+		if entryDay, _, ok := strings.Cut(strings.TrimSpace(text[offset:lineEnd]), ":"); ok {
+			if n, err := strconv.Atoi(entryDay); err == nil && n > data.Day {
+				insertAt = offset
+				break
+			}
+		}
+		offset = lineEnd
+	}
+	entry := fmt.Sprintf("\t%d: %s.Solver{},\n", data.Day, data.Package)
+	text = text[:insertAt] + entry + text[insertAt:]
+
+	formatted, err := format.Source([]byte(text))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, formatted, 0o644)
+}
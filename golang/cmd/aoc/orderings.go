@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day05"
+)
+
+func runOrderings(args []string) error {
+	flags := flag.NewFlagSet("orderings", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(5), "day 5 style rules and updates to check")
+	format := flags.String("format", "text", "output format: text or json")
+	all := flags.Bool("all", false, "include updates that are already in order")
+	count := flags.Bool("count", false, "count each update's valid orders and list its possible middle pages instead")
+	enumerate := flags.Int("enumerate", 0, "list the valid orders of this update, counting from 1, instead")
+	limit := flags.Int("limit", 100, "most orders to list with --enumerate")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown --format %q, expected text or json", *format)
+	}
+
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+	if *count {
+		return printOrderingCounts(string(input))
+	}
+	if *enumerate != 0 {
+		return printOrderings(string(input), *enumerate, *limit)
+	}
+
+	reports, err := day05.CheckUpdates(string(input))
+	if err != nil {
+		return err
+	}
+	if !*all {
+		rejected := make([]day05.UpdateReport, 0)
+		for _, report := range reports {
+			if !report.Valid {
+				rejected = append(rejected, report)
+			}
+		}
+		reports = rejected
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	}
+	for _, report := range reports {
+		printUpdateReport(report)
+	}
+	return nil
+}
+
+func printUpdateReport(report day05.UpdateReport) {
+	if report.Valid {
+		fmt.Printf("update %d %v: in order\n", report.Update, report.Pages)
+		return
+	}
+	fmt.Printf("update %d %v: %d rule(s) broken\n", report.Update, report.Pages, len(report.Violations))
+	for _, v := range report.Violations {
+		fmt.Printf("  %d|%d broken: %d at index %d comes after %d at index %d\n", v.Before, v.After, v.Before, v.BeforeIndex, v.After, v.AfterIndex)
+	}
+	if report.Error != "" {
+		fmt.Printf("  can't fix: %s\n", report.Error)
+		return
+	}
+	fmt.Printf("  fixed to %v in %d move(s)\n", report.Fixed, len(report.Moves))
+	for _, m := range report.Moves {
+		fmt.Printf("  move %d from index %d to %d\n", m.Page, m.From, m.To)
+	}
+	if len(report.Tied) > 0 {
+		fmt.Printf("  order not unique: rules don't order pages %v, which keep their update order\n", report.Tied)
+	}
+}
+
+func printOrderingCounts(input string) error {
+	rules, updates, err := day05.Parse(input)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "UPDATE\tPAGES\tORDERS\tMIDDLE_PAGES")
+	for i, pages := range updates {
+		orders, err := rules.CountOrderings(pages)
+		if err != nil {
+			fmt.Fprintf(w, "%d\t%d\t%s\t\n", i+1, len(pages), err)
+			continue
+		}
+		middles, err := rules.MiddlePages(pages)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%v\n", i+1, len(pages), orders, middles)
+	}
+	return w.Flush()
+}
+
+func printOrderings(input string, update int, limit int) error {
+	rules, updates, err := day05.Parse(input)
+	if err != nil {
+		return err
+	}
+	if update < 1 || update > len(updates) {
+		return fmt.Errorf("--enumerate must be between 1 and %d, got %d", len(updates), update)
+	}
+
+	listed := 0
+	for order := range rules.Orderings(updates[update-1]) {
+		if listed == limit {
+			fmt.Printf("stopped after %d order(s), raise --limit for more\n", limit)
+			return nil
+		}
+		fmt.Println(order)
+		listed += 1
+	}
+	fmt.Printf("%d order(s)\n", listed)
+	return nil
+}
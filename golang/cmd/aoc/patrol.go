@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day06"
+)
+
+func runPatrol(args []string) error {
+	flags := flag.NewFlagSet("patrol", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(6), "day 6 style floor plan, with any number of guards")
+	configPath := flags.String("config", "", "JSON patrol config giving each guard's behaviour (defaults to the puzzle's guard)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config := day06.DefaultPatrolConfig
+	if *configPath != "" {
+		file, err := os.Open(*configPath)
+		if err != nil {
+			return err
+		}
+		config, err = day06.LoadPatrolConfig(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", *configPath, err)
+		}
+	}
+
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+	report, err := day06.Patrol(string(input), config)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "GUARD\tSTART\tTURN\tSTEP\tIGNORE_O\tCOVERED\tTICKS\tOUTCOME")
+	for i, g := range report.Guards {
+		outcome := "loops"
+		if g.Left {
+			outcome = "leaves"
+		} else if g.Trapped {
+			outcome = "trapped"
+		}
+		fmt.Fprintf(
+			w,
+			"%d\t%d,%d%s\t%s\t%d\t%t\t%d\t%d\t%s\n",
+			i+1, g.Start.Row, g.Start.Col, g.Start.Facing,
+			g.Behaviour.Turn, g.Behaviour.Step, g.Behaviour.IgnoreO,
+			g.Covered, g.Ticks, outcome,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	multiStep := false
+	for _, g := range report.Guards {
+		if g.Behaviour.Step > 1 {
+			multiStep = true
+		}
+	}
+	for _, c := range report.Collisions {
+		how := "meet at"
+		if c.Swap {
+			how = "swap places at"
+		}
+		when := fmt.Sprintf("tick %d", c.Tick)
+		if multiStep {
+			when = fmt.Sprintf("tick %d step %d", c.Tick, c.Step)
+		}
+		fmt.Printf("%s: guards %v %s %d,%d\n", when, c.Guards, how, c.Cell.Row, c.Cell.Col)
+	}
+	fmt.Printf("%d cell(s) covered in %d tick(s), %d collision(s)\n", report.Covered, report.Ticks, len(report.Collisions))
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"advent_of_code_2024/internal/day01"
+)
+
+func runReconcile(args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(1), "two column list file to reconcile")
+	runSize := flags.Int("run-size", 0, "entries per list to sort in memory before spilling to disk (0 for the default)")
+	maxFanIn := flags.Int("max-fan-in", 0, "most sorted runs per list to merge at once (0 for the default of 64)")
+	tempDir := flags.String("temp-dir", "", "directory for the sorted runs (defaults to the system temp directory)")
+	showPairs := flags.Bool("pairs", false, "print every pair of entries and their distance")
+	showSimilarity := flags.Bool("similarity", false, "print the similarity score broken down by value")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	file, err := os.Open(*inputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	out := bufio.NewWriter(os.Stdout)
+	opts := day01.ExternalOptions{RunSize: *runSize, MaxFanIn: *maxFanIn, TempDir: *tempDir}
+	if *showPairs {
+		fmt.Fprintln(out, "LEFT_INDEX\tRIGHT_INDEX\tLEFT\tRIGHT\tDISTANCE")
+		opts.OnPair = func(p day01.Pair) error {
+			_, err := fmt.Fprintf(out, "%d\t%d\t%d\t%d\t%d\n", p.LeftIndex, p.RightIndex, p.Left, p.Right, p.Distance)
+			return err
+		}
+	}
+	if *showSimilarity {
+		// The similarity pass starts once every pair is written, so its header
+		// goes out with the first term.
+		headerWritten := false
+		opts.OnSimilarity = func(t day01.SimilarityTerm) error {
+			if !headerWritten {
+				if *showPairs {
+					fmt.Fprintln(out)
+				}
+				fmt.Fprintln(out, "VALUE\tLEFT_COUNT\tRIGHT_COUNT\tSCORE")
+				headerWritten = true
+			}
+			_, err := fmt.Fprintf(out, "%d\t%d\t%d\t%d\n", t.Value, t.LeftCount, t.RightCount, t.Score)
+			return err
+		}
+	}
+
+	totals, err := day01.ReconcileExternal(file, opts)
+	if err != nil {
+		return err
+	}
+	if *showPairs || *showSimilarity {
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "entries: %d\ntotal distance: %d\nsimilarity score: %d\n", totals.Entries, totals.TotalDistance, totals.SimilarityScore)
+	return out.Flush()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day02"
+)
+
+func runSafety(args []string) error {
+	flags := flag.NewFlagSet("safety", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(2), "file of day 2 style reports")
+	rulesPath := flags.String("rules", "", "JSON rule set to check against (defaults to the puzzle's rules)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	rules := day02.DefaultRules
+	if *rulesPath != "" {
+		file, err := os.Open(*rulesPath)
+		if err != nil {
+			return err
+		}
+		rules, err = day02.LoadRuleSet(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", *rulesPath, err)
+		}
+	}
+
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+	unsafe, err := day02.FindUnsafeReports(string(input), rules)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPORT\tRULE\tINDEX\tREASON\tLEVELS")
+	for _, report := range unsafe {
+		levels := make([]string, len(report.Levels))
+		for i, level := range report.Levels {
+			levels[i] = strconv.Itoa(level)
+		}
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%d\t%s\t%s\n",
+			report.Number,
+			report.Verdict.Rule,
+			report.Verdict.Index,
+			report.Verdict.Reason,
+			strings.Join(levels, " "),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d unsafe report(s)\n", len(unsafe))
+	return nil
+}
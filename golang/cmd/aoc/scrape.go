@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"advent_of_code_2024/internal/day03"
+)
+
+func runScrape(args []string) error {
+	flags := flag.NewFlagSet("scrape", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(3), "corrupted memory or log file to scrape")
+	names := flags.String("instructions", "mul,do,don't", "comma separated instructions to recognise: mul, do, don't, add, sub and reset")
+	trace := flags.Bool("trace", false, "print every instruction run with its byte offset")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	in, err := day03.NewInterpreter()
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Split(*names, ",") {
+		i := slices.IndexFunc(day03.Builtins, func(instruction day03.Instruction) bool {
+			return instruction.Name == name
+		})
+		if i == -1 {
+			return fmt.Errorf("unknown instruction %q", name)
+		}
+		if err := in.Register(day03.Builtins[i]); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(*inputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Steps are written as they run rather than lined up with a tabwriter, as
+	// a large capture can have far too many to hold.
+	out := bufio.NewWriter(os.Stdout)
+	var onStep func(day03.Step) error
+	if *trace {
+		fmt.Fprintln(out, "OFFSET\tINSTRUCTION\tENABLED\tENABLED_SUM\tDISABLED_SUM")
+		onStep = func(step day03.Step) error {
+			_, err := fmt.Fprintf(out, "%d\t%s\t%t\t%d\t%d\n", step.Offset, step.Text, step.State.Enabled, step.State.EnabledSum, step.State.DisabledSum())
+			return err
+		}
+	}
+	state, err := in.RunReader(bufio.NewReader(file), onStep)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "sum: %d\nenabled sum: %d\ndisabled sum: %d\n", state.Sum, state.EnabledSum, state.DisabledSum())
+	return out.Flush()
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"advent_of_code_2024/internal/days"
+	"advent_of_code_2024/internal/solver"
+)
+
+// maxInputBytes caps request bodies. The largest real input is well under
+// 100KiB, so this leaves plenty of headroom for generated ones.
+const maxInputBytes = 8 << 20
+
+// solveResponse is the JSON body of every reply from POST /days/{n}/parts/{p}.
+// Error is set instead of Answer when the input could not be solved.
+type solveResponse struct {
+	Day        int     `json:"day"`
+	Part       int     `json:"part"`
+	Answer     string  `json:"answer,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type solveOutcome struct {
+	answer   string
+	duration time.Duration
+	err      error
+}
+
+// solveServer runs solvers on request bodies. slots holds one token per solve
+// allowed to run at once. Solvers can't be interrupted, so a solve that times
+// out keeps running: it moves its token from slots to abandoned, freeing the
+// slot for other requests. Once abandoned is full, timed out solves keep
+// their slot until they finish, so no more than cap(slots)+cap(abandoned)
+// solves ever run at once.
+type solveServer struct {
+	slots     chan struct{}
+	abandoned chan struct{}
+	timeout   time.Duration
+	// solvers looks up the solver for a day, which is days.Get outside of
+	// tests.
+	solvers func(day int) (solver.Solver, bool)
+}
+
+func newSolveServer(maxConcurrent int, maxAbandoned int, timeout time.Duration) *solveServer {
+	return &solveServer{
+		slots:     make(chan struct{}, maxConcurrent),
+		abandoned: make(chan struct{}, maxAbandoned),
+		timeout:   timeout,
+		solvers:   days.Get,
+	}
+}
+
+// solveToken is the token a running solve holds, in whichever pool it is
+// currently counted against.
+type solveToken struct {
+	mu       sync.Mutex
+	pool     chan struct{}
+	released bool
+}
+
+func (t *solveToken) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.released {
+		<-t.pool
+		t.released = true
+	}
+}
+
+// moveTo counts the solve against pool instead, if it is still running and
+// pool has room, and reports whether it did.
+func (t *solveToken) moveTo(pool chan struct{}) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.released {
+		return false
+	}
+	select {
+	case pool <- struct{}{}:
+		<-t.pool
+		t.pool = pool
+		return true
+	default:
+		return false
+	}
+}
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	timeout := flags.Duration("timeout", 30*time.Second, "longest a single solve may take before the request fails")
+	maxConcurrent := flags.Int("max-concurrent", runtime.NumCPU(), "maximum number of solves running at once")
+	maxAbandoned := flags.Int("max-abandoned", runtime.NumCPU(), "maximum number of timed out solves left running in the background without holding a slot")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *timeout <= 0 {
+		return fmt.Errorf("--timeout must be positive, got %v", *timeout)
+	}
+	if *maxConcurrent < 1 {
+		return fmt.Errorf("--max-concurrent must be at least 1, got %d", *maxConcurrent)
+	}
+	if *maxAbandoned < 0 {
+		return fmt.Errorf("--max-abandoned can't be negative, got %d", *maxAbandoned)
+	}
+
+	s := newSolveServer(*maxConcurrent, *maxAbandoned, *timeout)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("listening on %s", *addr)
+	return server.ListenAndServe()
+}
+
+func (s *solveServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /days/{n}/parts/{p}", s.handleSolve)
+	return mux
+}
+
+func (s *solveServer) handleSolve(w http.ResponseWriter, r *http.Request) {
+	day, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil {
+		writeSolveResponse(w, http.StatusNotFound, solveResponse{Error: fmt.Sprintf("invalid day %q", r.PathValue("n"))})
+		return
+	}
+	part, err := strconv.Atoi(r.PathValue("p"))
+	if err != nil || part < 1 || part > 2 {
+		writeSolveResponse(w, http.StatusNotFound, solveResponse{Day: day, Error: fmt.Sprintf("invalid part %q, expected 1 or 2", r.PathValue("p"))})
+		return
+	}
+	resp := solveResponse{Day: day, Part: part}
+	daySolver, ok := s.solvers(day)
+	if !ok {
+		resp.Error = fmt.Sprintf("no solver for day %d", day)
+		writeSolveResponse(w, http.StatusNotFound, resp)
+		return
+	}
+
+	input, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInputBytes))
+	if err != nil {
+		resp.Error = fmt.Sprintf("reading input: %v", err)
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeSolveResponse(w, status, resp)
+		return
+	}
+
+	// Waiting for a free slot counts against the timeout too, so a backed up
+	// server fails fast rather than queueing requests indefinitely.
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	defer cancel()
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		resp.Error = "timed out waiting for a free solver"
+		writeSolveResponse(w, http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	token := &solveToken{pool: s.slots}
+	done := make(chan solveOutcome, 1)
+	go func() {
+		defer token.release()
+		start := time.Now()
+		answer, err := solver.Solve(daySolver, part, string(input))
+		done <- solveOutcome{answer: answer, duration: time.Since(start), err: err}
+	}()
+
+	select {
+	case outcome := <-done:
+		resp.DurationMS = float64(outcome.duration.Microseconds()) / 1000
+		switch {
+		case errors.Is(outcome.err, solver.ErrNoPart):
+			resp.Error = outcome.err.Error()
+			writeSolveResponse(w, http.StatusNotFound, resp)
+		case outcome.err != nil:
+			resp.Error = outcome.err.Error()
+			writeSolveResponse(w, http.StatusUnprocessableEntity, resp)
+		default:
+			resp.Answer = outcome.answer
+			writeSolveResponse(w, http.StatusOK, resp)
+		}
+	case <-ctx.Done():
+		token.moveTo(s.abandoned)
+		resp.DurationMS = float64(s.timeout.Microseconds()) / 1000
+		resp.Error = fmt.Sprintf("solve did not finish within %v", s.timeout)
+		writeSolveResponse(w, http.StatusGatewayTimeout, resp)
+	}
+}
+
+func writeSolveResponse(w http.ResponseWriter, status int, resp solveResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("writing response: %v", err)
+	}
+}
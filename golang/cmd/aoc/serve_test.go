@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"advent_of_code_2024/internal/days"
+	"advent_of_code_2024/internal/solver"
+)
+
+const dayOneExample = "3   4\n4   3\n2   5\n1   3\n3   9\n3   3\n"
+
+// blockingSolver doesn't answer until unblock is closed, standing in for a
+// solve that takes too long.
+type blockingSolver struct {
+	unblock chan struct{}
+}
+
+func (b blockingSolver) PartOne(string) (string, error) {
+	<-b.unblock
+	return "done", nil
+}
+
+func (b blockingSolver) PartTwo(input string) (string, error) {
+	return b.PartOne(input)
+}
+
+// newTestServer serves the real solvers along with a blocking one as day 99.
+func newTestServer(t *testing.T, maxConcurrent int, maxAbandoned int, timeout time.Duration) (*httptest.Server, chan struct{}) {
+	t.Helper()
+	unblock := make(chan struct{})
+	s := newSolveServer(maxConcurrent, maxAbandoned, timeout)
+	s.solvers = func(day int) (solver.Solver, bool) {
+		if day == 99 {
+			return blockingSolver{unblock: unblock}, true
+		}
+		return days.Get(day)
+	}
+	server := httptest.NewServer(s.routes())
+	t.Cleanup(func() {
+		// Let any abandoned solves finish before the server shuts down.
+		select {
+		case <-unblock:
+		default:
+			close(unblock)
+		}
+		server.Close()
+	})
+	return server, unblock
+}
+
+func postSolve(t *testing.T, server *httptest.Server, path string, input string) (int, solveResponse) {
+	t.Helper()
+	res, err := http.Post(server.URL+path, "text/plain", strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var resp solveResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return res.StatusCode, resp
+}
+
+func TestServeSolve(t *testing.T) {
+	server, _ := newTestServer(t, 2, 2, 100*time.Millisecond)
+	tests := []struct {
+		name       string
+		path       string
+		input      string
+		wantStatus int
+		wantAnswer string
+		wantError  bool
+	}{
+		{name: "solve", path: "/days/1/parts/1", input: dayOneExample, wantStatus: http.StatusOK, wantAnswer: "11"},
+		{name: "parse error", path: "/days/1/parts/1", input: "not numbers\n", wantStatus: http.StatusUnprocessableEntity, wantError: true},
+		{name: "unsolvable input", path: "/days/13/parts/2", input: "Button A: X+1, Y+1\nButton B: X+2, Y+2\nPrize: X=10, Y=10\n", wantStatus: http.StatusUnprocessableEntity, wantError: true},
+		{name: "unknown day", path: "/days/26/parts/1", input: dayOneExample, wantStatus: http.StatusNotFound, wantError: true},
+		{name: "invalid part", path: "/days/1/parts/3", input: dayOneExample, wantStatus: http.StatusNotFound, wantError: true},
+		{name: "no such part", path: "/days/25/parts/2", input: "", wantStatus: http.StatusNotFound, wantError: true},
+		{name: "timeout", path: "/days/99/parts/1", input: "", wantStatus: http.StatusGatewayTimeout, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := postSolve(t, server, tt.path, tt.input)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d (response %+v)", status, tt.wantStatus, resp)
+			}
+			if resp.Answer != tt.wantAnswer {
+				t.Errorf("answer = %q, want %q", resp.Answer, tt.wantAnswer)
+			}
+			if (resp.Error != "") != tt.wantError {
+				t.Errorf("error = %q, want an error: %v", resp.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestServeTimedOutSolvesFreeTheirSlot(t *testing.T) {
+	server, unblock := newTestServer(t, 1, 1, 100*time.Millisecond)
+
+	// The first slow solve is abandoned, giving its only slot back.
+	if status, _ := postSolve(t, server, "/days/99/parts/1", ""); status != http.StatusGatewayTimeout {
+		t.Fatalf("first slow solve status = %d, want %d", status, http.StatusGatewayTimeout)
+	}
+	if status, resp := postSolve(t, server, "/days/1/parts/1", dayOneExample); status != http.StatusOK {
+		t.Fatalf("solve after one timeout status = %d, want %d (response %+v)", status, http.StatusOK, resp)
+	}
+
+	// With no room left to abandon it, the second keeps the slot.
+	if status, _ := postSolve(t, server, "/days/99/parts/1", ""); status != http.StatusGatewayTimeout {
+		t.Fatalf("second slow solve status = %d, want %d", status, http.StatusGatewayTimeout)
+	}
+	if status, _ := postSolve(t, server, "/days/1/parts/1", dayOneExample); status != http.StatusServiceUnavailable {
+		t.Fatalf("solve with every slot taken status = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+
+	// Once the slow solves finish, their slots come back.
+	close(unblock)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, _ := postSolve(t, server, "/days/1/parts/1", dayOneExample)
+		if status == http.StatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("solve after the slow solves finished status = %d, want %d", status, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"advent_of_code_2024/internal/day04"
+)
+
+func runWordSearch(args []string) error {
+	flags := flag.NewFlagSet("wordsearch", flag.ContinueOnError)
+	inputPath := flags.String("input", defaultInputPath(4), "grid of letters to search")
+	words := flags.String("words", "XMAS", "comma separated words to find")
+	toroidal := flags.Bool("toroidal", false, "let words wrap around the edges of the grid")
+	render := flags.String("render", "", "draw the matches instead of listing them: text, color or html")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	switch *render {
+	case "", "text", "color", "html":
+	default:
+		return fmt.Errorf("unknown --render %q, expected text, color or html", *render)
+	}
+
+	input, err := os.ReadFile(*inputPath)
+	if err != nil {
+		return err
+	}
+	grid, err := day04.ParseGrid(string(input))
+	if err != nil {
+		return fmt.Errorf("%s: %w", *inputPath, err)
+	}
+	matches, err := day04.Search(grid, strings.Split(*words, ","), day04.SearchOptions{Toroidal: *toroidal})
+	if err != nil {
+		return err
+	}
+
+	switch *render {
+	case "text", "color":
+		fmt.Print(day04.Render(grid, day04.WordCells(grid, matches), day04.RenderOptions{Color: *render == "color"}))
+		return nil
+	case "html":
+		fmt.Print(day04.RenderHTML(grid, day04.WordCells(grid, matches)))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORD\tROW\tCOL\tDIRECTION")
+	for _, m := range matches {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", m.Word, m.Row, m.Col, m.Direction)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d match(es)\n", len(matches))
+	return nil
+}
@@ -0,0 +1,45 @@
+// Package checked provides int arithmetic that reports overflow rather than
+// silently wrapping, in the spirit of Rust's checked_add and checked_mul.
+package checked
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrOverflow is wrapped by solvers when a value no longer fits in an int.
+var ErrOverflow = errors.New("integer overflow")
+
+// Add returns a + b, and false if the result overflowed.
+func Add(a, b int) (int, bool) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return sum, false
+	}
+	return sum, true
+}
+
+// Sub returns a - b, and false if the result overflowed.
+func Sub(a, b int) (int, bool) {
+	difference := a - b
+	if (b > 0 && difference > a) || (b < 0 && difference < a) {
+		return difference, false
+	}
+	return difference, true
+}
+
+// Mul returns a * b, and false if the result overflowed.
+func Mul(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	// MinInt * -1 wraps back to MinInt, which the division check can't see.
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return product, false
+	}
+	if product/b != a {
+		return product, false
+	}
+	return product, true
+}
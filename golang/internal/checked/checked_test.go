@@ -0,0 +1,91 @@
+package checked
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		a, b int
+		want int
+		ok   bool
+	}{
+		{1, 2, 3, true},
+		{-1, -2, -3, true},
+		{math.MaxInt, 0, math.MaxInt, true},
+		{math.MaxInt - 1, 1, math.MaxInt, true},
+		{math.MaxInt, 1, 0, false},
+		{math.MaxInt, math.MaxInt, 0, false},
+		{math.MinInt, 0, math.MinInt, true},
+		{math.MinInt + 1, -1, math.MinInt, true},
+		{math.MinInt, -1, 0, false},
+		{math.MinInt, math.MinInt, 0, false},
+		{math.MaxInt, math.MinInt, -1, true},
+	}
+	for _, test := range tests {
+		got, ok := Add(test.a, test.b)
+		if ok != test.ok || (ok && got != test.want) {
+			t.Errorf("Add(%d, %d) = %d, %t, want %d, %t", test.a, test.b, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestSub(t *testing.T) {
+	tests := []struct {
+		a, b int
+		want int
+		ok   bool
+	}{
+		{3, 2, 1, true},
+		{-3, -2, -1, true},
+		{math.MaxInt, 0, math.MaxInt, true},
+		{math.MaxInt, -1, 0, false},
+		{math.MaxInt - 1, -1, math.MaxInt, true},
+		{math.MinInt, 0, math.MinInt, true},
+		{math.MinInt, 1, 0, false},
+		{math.MinInt + 1, 1, math.MinInt, true},
+		{0, math.MinInt, 0, false},
+		{-1, math.MinInt, math.MaxInt, true},
+		{math.MinInt, math.MinInt, 0, true},
+		{math.MaxInt, math.MaxInt, 0, true},
+	}
+	for _, test := range tests {
+		got, ok := Sub(test.a, test.b)
+		if ok != test.ok || (ok && got != test.want) {
+			t.Errorf("Sub(%d, %d) = %d, %t, want %d, %t", test.a, test.b, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		a, b int
+		want int
+		ok   bool
+	}{
+		{6, 7, 42, true},
+		{-6, 7, -42, true},
+		{0, math.MinInt, 0, true},
+		{math.MaxInt, 0, 0, true},
+		{math.MaxInt, 1, math.MaxInt, true},
+		{math.MaxInt, -1, -math.MaxInt, true},
+		{math.MaxInt, 2, 0, false},
+		{math.MaxInt, math.MaxInt, 0, false},
+		{math.MinInt, 1, math.MinInt, true},
+		{math.MinInt, -1, 0, false},
+		{-1, math.MinInt, 0, false},
+		{math.MinInt, 2, 0, false},
+		{math.MinInt, math.MinInt, 0, false},
+		{math.MinInt / 2, 2, math.MinInt, true},
+		{math.MaxInt/2 + 1, 2, 0, false},
+		{1 << 31, 1 << 31, 1 << 62, true},
+		{1 << 32, 1 << 31, 0, false},
+	}
+	for _, test := range tests {
+		got, ok := Mul(test.a, test.b)
+		if ok != test.ok || (ok && got != test.want) {
+			t.Errorf("Mul(%d, %d) = %d, %t, want %d, %t", test.a, test.b, got, ok, test.want, test.ok)
+		}
+	}
+}
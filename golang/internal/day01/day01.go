@@ -0,0 +1,231 @@
+// Package day01 solves day 1, Historian Hysteria.
+package day01
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+// Pair is a left entry matched with the right entry of the same rank once both
+// lists are sorted. The indexes are positions in the original, unsorted lists.
+type Pair struct {
+	LeftIndex  int
+	RightIndex int
+	Left       int
+	Right      int
+	Distance   int
+}
+
+func newPair(left entry, right entry) Pair {
+	distance := left.value - right.value
+	if distance < 0 {
+		distance = -distance
+	}
+	return Pair{
+		LeftIndex:  left.index,
+		RightIndex: right.index,
+		Left:       left.value,
+		Right:      right.value,
+		Distance:   distance,
+	}
+}
+
+// SimilarityTerm is the share of the similarity score from one value that
+// appears in both lists.
+type SimilarityTerm struct {
+	Value      int
+	LeftCount  int
+	RightCount int
+	Score      int
+}
+
+func newSimilarityTerm(value int, leftCount int, rightCount int) (SimilarityTerm, error) {
+	score, ok := checked.Mul(value, leftCount)
+	if ok {
+		score, ok = checked.Mul(score, rightCount)
+	}
+	if !ok {
+		return SimilarityTerm{}, fmt.Errorf("%w: similarity of %d seen %d times on the left and %d on the right", checked.ErrOverflow, value, leftCount, rightCount)
+	}
+	return SimilarityTerm{Value: value, LeftCount: leftCount, RightCount: rightCount, Score: score}, nil
+}
+
+// Report shows how both answers were reached rather than just the totals.
+type Report struct {
+	// Pairs is in sorted order, so Pairs[i] holds the i-th smallest entry of
+	// each list.
+	Pairs         []Pair
+	TotalDistance int
+	// Similarity holds one term per value found in both lists, in ascending
+	// order of value.
+	Similarity      []SimilarityTerm
+	SimilarityScore int
+}
+
+// NewReport parses input and reports which entries were paired and where the
+// similarity score came from.
+func NewReport(input string) (Report, error) {
+	lhs, rhs, err := parseInput(input)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Pairs: pairLists(lhs, rhs)}
+	report.TotalDistance, err = sumDistances(report.Pairs)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Similarity, err = similarityTerms(lhs, rhs)
+	if err != nil {
+		return Report{}, err
+	}
+	report.SimilarityScore, err = sumSimilarity(report.Similarity)
+	if err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+// entry is a list value along with its position in the unsorted list. Sorting
+// by both keeps the pairing stable when a value repeats.
+type entry struct {
+	value int
+	index int
+}
+
+func compareEntries(a entry, b entry) int {
+	if a.value != b.value {
+		return cmp.Compare(a.value, b.value)
+	}
+	return cmp.Compare(a.index, b.index)
+}
+
+func sortedEntries(values []int) []entry {
+	entries := make([]entry, len(values))
+	for i, value := range values {
+		entries[i] = entry{value: value, index: i}
+	}
+	slices.SortFunc(entries, compareEntries)
+	return entries
+}
+
+// pairLists matches up two lists of the same length by rank without
+// reordering them.
+func pairLists(lhs []int, rhs []int) []Pair {
+	lhsEntries := sortedEntries(lhs)
+	rhsEntries := sortedEntries(rhs)
+
+	pairs := make([]Pair, len(lhsEntries))
+	for i := range lhsEntries {
+		pairs[i] = newPair(lhsEntries[i], rhsEntries[i])
+	}
+	return pairs
+}
+
+func similarityTerms(lhs []int, rhs []int) ([]SimilarityTerm, error) {
+	lhsCounts := lo.CountValues(lhs)
+	rhsCounts := lo.CountValues(rhs)
+
+	terms := make([]SimilarityTerm, 0)
+	for _, value := range slices.Sorted(maps.Keys(lhsCounts)) {
+		if rhsCounts[value] == 0 {
+			continue
+		}
+		term, err := newSimilarityTerm(value, lhsCounts[value], rhsCounts[value])
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+func sumDistances(pairs []Pair) (int, error) {
+	total := 0
+	for _, pair := range pairs {
+		var ok bool
+		total, ok = checked.Add(total, pair.Distance)
+		if !ok {
+			return 0, fmt.Errorf("%w: total distance", checked.ErrOverflow)
+		}
+	}
+	return total, nil
+}
+
+func sumSimilarity(terms []SimilarityTerm) (int, error) {
+	total := 0
+	for _, term := range terms {
+		var ok bool
+		total, ok = checked.Add(total, term.Score)
+		if !ok {
+			return 0, fmt.Errorf("%w: similarity score", checked.ErrOverflow)
+		}
+	}
+	return total, nil
+}
+
+// parseLine reads the left and right entries from one line of input.
+func parseLine(line string) (int, int, error) {
+	tokens := strings.Fields(line)
+	if len(tokens) != 2 {
+		return 0, 0, fmt.Errorf("expected two whitespace separated numbers, got %q", line)
+	}
+	lhs, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	rhs, err := strconv.Atoi(tokens[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return lhs, rhs, nil
+}
+
+func parseInput(input string) ([]int, []int, error) {
+	lists, err := LoadLists(strings.NewReader(input), LoadOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(lists.Columns) != 2 {
+		return nil, nil, fmt.Errorf("expected two lists, got %d", len(lists.Columns))
+	}
+	return lists.Columns[0], lists.Columns[1], nil
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	lhs, rhs, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	total, err := sumDistances(pairLists(lhs, rhs))
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(total), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	lhs, rhs, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	terms, err := similarityTerms(lhs, rhs)
+	if err != nil {
+		return "", err
+	}
+	score, err := sumSimilarity(terms)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(score), nil
+}
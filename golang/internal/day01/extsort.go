@@ -0,0 +1,464 @@
+package day01
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+// defaultRunSize keeps each list's in-memory buffer to a few tens of megabytes.
+const defaultRunSize = 1 << 21
+
+// defaultFanIn is how many runs of a list are merged at once by default,
+// which keeps the open files well under the usual limit of 1024.
+const defaultFanIn = 64
+
+// entryBytes is the size of an entry in a run file: its value then its index,
+// each as a little endian int64.
+const entryBytes = 16
+
+// ExternalOptions configures ReconcileExternal.
+type ExternalOptions struct {
+	// RunSize is how many entries of each list are sorted in memory at a time
+	// before being spilled to a temporary file. Zero means defaultRunSize.
+	RunSize int
+	// MaxFanIn is the most runs of each list that are merged at once. With
+	// more runs than that, they are first merged in groups of MaxFanIn into
+	// longer runs, as many times as it takes. Zero means defaultFanIn.
+	MaxFanIn int
+	// TempDir is where the sorted runs are written. Empty means os.TempDir.
+	TempDir string
+	// OnPair, if set, is called for every pair in sorted order.
+	OnPair func(Pair) error
+	// OnSimilarity, if set, is called for every value found in both lists in
+	// ascending order.
+	OnSimilarity func(SimilarityTerm) error
+}
+
+// Totals are the answers to both parts for a list reconciled by
+// ReconcileExternal.
+type Totals struct {
+	Entries         int
+	TotalDistance   int
+	SimilarityScore int
+}
+
+// ReconcileExternal computes the same pairing and similarity as NewReport for
+// lists too large to hold in memory. Each list is sorted in runs of
+// opts.RunSize entries that are spilled to temporary files, and the runs are
+// merged down to at most opts.MaxFanIn per list. They are then merged back
+// together twice: once pairing the lists by rank and once joining them by
+// value.
+func ReconcileExternal(r io.Reader, opts ExternalOptions) (Totals, error) {
+	if opts.RunSize == 0 {
+		opts.RunSize = defaultRunSize
+	}
+	if opts.RunSize < 0 {
+		return Totals{}, fmt.Errorf("run size must be positive, got %d", opts.RunSize)
+	}
+	if opts.MaxFanIn == 0 {
+		opts.MaxFanIn = defaultFanIn
+	}
+	if opts.MaxFanIn < 2 {
+		return Totals{}, fmt.Errorf("max fan-in must be at least 2, got %d", opts.MaxFanIn)
+	}
+
+	dir, err := os.MkdirTemp(opts.TempDir, "day01-runs-")
+	if err != nil {
+		return Totals{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	lhsRuns := &runSpiller{dir: dir, prefix: "lhs", size: opts.RunSize}
+	rhsRuns := &runSpiller{dir: dir, prefix: "rhs", size: opts.RunSize}
+	totals := Totals{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		if scanner.Text() == "" {
+			continue
+		}
+		lhs, rhs, err := parseLine(scanner.Text())
+		if err != nil {
+			return Totals{}, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if err := lhsRuns.add(entry{value: lhs, index: totals.Entries}); err != nil {
+			return Totals{}, err
+		}
+		if err := rhsRuns.add(entry{value: rhs, index: totals.Entries}); err != nil {
+			return Totals{}, err
+		}
+		totals.Entries += 1
+	}
+	if err := scanner.Err(); err != nil {
+		return Totals{}, err
+	}
+	if err := lhsRuns.flush(); err != nil {
+		return Totals{}, err
+	}
+	if err := rhsRuns.flush(); err != nil {
+		return Totals{}, err
+	}
+	if err := lhsRuns.reduce(opts.MaxFanIn); err != nil {
+		return Totals{}, err
+	}
+	if err := rhsRuns.reduce(opts.MaxFanIn); err != nil {
+		return Totals{}, err
+	}
+
+	totals.TotalDistance, err = mergePairs(lhsRuns.runs, rhsRuns.runs, opts.OnPair)
+	if err != nil {
+		return Totals{}, err
+	}
+	totals.SimilarityScore, err = mergeSimilarity(lhsRuns.runs, rhsRuns.runs, opts.OnSimilarity)
+	if err != nil {
+		return Totals{}, err
+	}
+	return totals, nil
+}
+
+// runSpiller buffers one list's entries and writes them out as sorted runs.
+type runSpiller struct {
+	dir    string
+	prefix string
+	size   int
+	buffer []entry
+	runs   []string
+}
+
+func (rs *runSpiller) add(e entry) error {
+	rs.buffer = append(rs.buffer, e)
+	if len(rs.buffer) < rs.size {
+		return nil
+	}
+	return rs.flush()
+}
+
+func (rs *runSpiller) flush() error {
+	if len(rs.buffer) == 0 {
+		return nil
+	}
+	slices.SortFunc(rs.buffer, compareEntries)
+
+	path := filepath.Join(rs.dir, fmt.Sprintf("%s-%d", rs.prefix, len(rs.runs)))
+	rw, err := createRun(path)
+	if err != nil {
+		return err
+	}
+	for _, e := range rs.buffer {
+		if err := rw.write(e); err != nil {
+			rw.file.Close()
+			return err
+		}
+	}
+	if err := rw.close(); err != nil {
+		return err
+	}
+
+	rs.runs = append(rs.runs, path)
+	rs.buffer = rs.buffer[:0]
+	return nil
+}
+
+// reduce merges the runs in groups of fanIn, over as many passes as it takes
+// to leave at most fanIn of them, so that no merge opens more than fanIn
+// files at once.
+func (rs *runSpiller) reduce(fanIn int) error {
+	for pass := 1; len(rs.runs) > fanIn; pass++ {
+		merged := make([]string, 0, (len(rs.runs)+fanIn-1)/fanIn)
+		for start := 0; start < len(rs.runs); start += fanIn {
+			group := rs.runs[start:min(start+fanIn, len(rs.runs))]
+			if len(group) == 1 {
+				merged = append(merged, group[0])
+				continue
+			}
+			path := filepath.Join(rs.dir, fmt.Sprintf("%s-pass%d-%d", rs.prefix, pass, len(merged)))
+			if err := mergeRuns(group, path); err != nil {
+				return err
+			}
+			for _, run := range group {
+				if err := os.Remove(run); err != nil {
+					return err
+				}
+			}
+			merged = append(merged, path)
+		}
+		rs.runs = merged
+	}
+	return nil
+}
+
+// mergeRuns writes the entries of several sorted runs to path as one sorted
+// run.
+func mergeRuns(paths []string, path string) error {
+	m, err := newRunMerger(paths)
+	if err != nil {
+		return err
+	}
+	defer m.close()
+	rw, err := createRun(path)
+	if err != nil {
+		return err
+	}
+	for {
+		e, ok, err := m.next()
+		if err != nil {
+			rw.file.Close()
+			return err
+		}
+		if !ok {
+			return rw.close()
+		}
+		if err := rw.write(e); err != nil {
+			rw.file.Close()
+			return err
+		}
+	}
+}
+
+// runWriter writes a sorted run an entry at a time.
+type runWriter struct {
+	file *os.File
+	w    *bufio.Writer
+}
+
+func createRun(path string) (*runWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &runWriter{file: file, w: bufio.NewWriter(file)}, nil
+}
+
+func (rw *runWriter) write(e entry) error {
+	var record [entryBytes]byte
+	binary.LittleEndian.PutUint64(record[:8], uint64(e.value))
+	binary.LittleEndian.PutUint64(record[8:], uint64(e.index))
+	_, err := rw.w.Write(record[:])
+	return err
+}
+
+func (rw *runWriter) close() error {
+	if err := rw.w.Flush(); err != nil {
+		rw.file.Close()
+		return err
+	}
+	return rw.file.Close()
+}
+
+// runReader reads one sorted run, holding the entry it is currently on.
+type runReader struct {
+	file    *os.File
+	r       *bufio.Reader
+	current entry
+}
+
+func (rr *runReader) advance() (bool, error) {
+	var record [entryBytes]byte
+	if _, err := io.ReadFull(rr.r, record[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading %s: %w", rr.file.Name(), err)
+	}
+	rr.current = entry{
+		value: int(binary.LittleEndian.Uint64(record[:8])),
+		index: int(binary.LittleEndian.Uint64(record[8:])),
+	}
+	return true, nil
+}
+
+// runHeap orders run readers by their current entry.
+type runHeap []*runReader
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return compareEntries(h[i].current, h[j].current) < 0 }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(*runReader)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// runMerger yields the entries of several sorted runs as one sorted stream.
+type runMerger struct {
+	readers []*runReader
+	heap    runHeap
+}
+
+func newRunMerger(paths []string) (*runMerger, error) {
+	m := &runMerger{}
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			m.close()
+			return nil, err
+		}
+		rr := &runReader{file: file, r: bufio.NewReader(file)}
+		m.readers = append(m.readers, rr)
+		ok, err := rr.advance()
+		if err != nil {
+			m.close()
+			return nil, err
+		}
+		if ok {
+			m.heap = append(m.heap, rr)
+		}
+	}
+	heap.Init(&m.heap)
+	return m, nil
+}
+
+func (m *runMerger) peek() (entry, bool) {
+	if len(m.heap) == 0 {
+		return entry{}, false
+	}
+	return m.heap[0].current, true
+}
+
+func (m *runMerger) next() (entry, bool, error) {
+	if len(m.heap) == 0 {
+		return entry{}, false, nil
+	}
+	top := m.heap[0]
+	e := top.current
+	ok, err := top.advance()
+	if err != nil {
+		return entry{}, false, err
+	}
+	if ok {
+		heap.Fix(&m.heap, 0)
+	} else {
+		heap.Pop(&m.heap)
+	}
+	return e, true, nil
+}
+
+// nextGroup consumes every entry with the smallest remaining value and
+// returns that value and how many there were.
+func (m *runMerger) nextGroup() (int, int, bool, error) {
+	first, ok, err := m.next()
+	if !ok || err != nil {
+		return 0, 0, false, err
+	}
+	count := 1
+	for {
+		e, ok := m.peek()
+		if !ok || e.value != first.value {
+			return first.value, count, true, nil
+		}
+		if _, _, err := m.next(); err != nil {
+			return 0, 0, false, err
+		}
+		count += 1
+	}
+}
+
+func (m *runMerger) close() {
+	for _, rr := range m.readers {
+		rr.file.Close()
+	}
+}
+
+// mergePairs walks both lists in sorted order, pairing entries by rank.
+func mergePairs(lhsRuns []string, rhsRuns []string, onPair func(Pair) error) (int, error) {
+	lhs, err := newRunMerger(lhsRuns)
+	if err != nil {
+		return 0, err
+	}
+	defer lhs.close()
+	rhs, err := newRunMerger(rhsRuns)
+	if err != nil {
+		return 0, err
+	}
+	defer rhs.close()
+
+	total := 0
+	for {
+		left, ok, err := lhs.next()
+		if err != nil || !ok {
+			return total, err
+		}
+		right, ok, err := rhs.next()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, errors.New("right list ran out before the left")
+		}
+
+		pair := newPair(left, right)
+		total, ok = checked.Add(total, pair.Distance)
+		if !ok {
+			return 0, fmt.Errorf("%w: total distance", checked.ErrOverflow)
+		}
+		if onPair != nil {
+			if err := onPair(pair); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
+
+// mergeSimilarity walks both lists in sorted order a value at a time, joining
+// the values they have in common.
+func mergeSimilarity(lhsRuns []string, rhsRuns []string, onTerm func(SimilarityTerm) error) (int, error) {
+	lhs, err := newRunMerger(lhsRuns)
+	if err != nil {
+		return 0, err
+	}
+	defer lhs.close()
+	rhs, err := newRunMerger(rhsRuns)
+	if err != nil {
+		return 0, err
+	}
+	defer rhs.close()
+
+	total := 0
+	rightValue, rightCount, rightOK, err := rhs.nextGroup()
+	if err != nil {
+		return 0, err
+	}
+	for rightOK {
+		leftValue, leftCount, leftOK, err := lhs.nextGroup()
+		if err != nil || !leftOK {
+			return total, err
+		}
+		for rightOK && rightValue < leftValue {
+			rightValue, rightCount, rightOK, err = rhs.nextGroup()
+			if err != nil {
+				return 0, err
+			}
+		}
+		if !rightOK || rightValue != leftValue {
+			continue
+		}
+
+		term, err := newSimilarityTerm(leftValue, leftCount, rightCount)
+		if err != nil {
+			return 0, err
+		}
+		var ok bool
+		total, ok = checked.Add(total, term.Score)
+		if !ok {
+			return 0, fmt.Errorf("%w: similarity score", checked.ErrOverflow)
+		}
+		if onTerm != nil {
+			if err := onTerm(term); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,164 @@
+package day01
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestReconcileExternal checks the external sort against NewReport, with run
+// sizes small enough that every input spills several runs to merge.
+func TestReconcileExternal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := range 50 {
+		entries := 1 + rng.Intn(40)
+		var sb strings.Builder
+		for range entries {
+			// A small range of values makes repeats, which pair by index.
+			fmt.Fprintf(&sb, "%d   %d\n", rng.Intn(10), rng.Intn(10))
+		}
+		input := sb.String()
+		runSize := 1 + rng.Intn(5)
+
+		t.Run(fmt.Sprintf("case%d", i), func(t *testing.T) {
+			want, err := NewReport(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pairs := make([]Pair, 0)
+			terms := make([]SimilarityTerm, 0)
+			got, err := ReconcileExternal(strings.NewReader(input), ExternalOptions{
+				RunSize: runSize,
+				TempDir: t.TempDir(),
+				OnPair: func(p Pair) error {
+					pairs = append(pairs, p)
+					return nil
+				},
+				OnSimilarity: func(term SimilarityTerm) error {
+					terms = append(terms, term)
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Entries != entries || got.TotalDistance != want.TotalDistance || got.SimilarityScore != want.SimilarityScore {
+				t.Errorf("ReconcileExternal() = %+v, want %d entries, distance %d and similarity %d",
+					got, entries, want.TotalDistance, want.SimilarityScore)
+			}
+			if !slices.Equal(pairs, want.Pairs) {
+				t.Errorf("pairs = %v, want %v", pairs, want.Pairs)
+			}
+			if !slices.Equal(terms, want.Similarity) {
+				t.Errorf("similarity terms = %v, want %v", terms, want.Similarity)
+			}
+		})
+	}
+}
+
+func TestReconcileExternalErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  ExternalOptions
+	}{
+		{name: "bad line", input: "1   2\nx   3\n"},
+		{name: "negative run size", input: "1   2\n", opts: ExternalOptions{RunSize: -1}},
+		{name: "fan-in of one", input: "1   2\n", opts: ExternalOptions{MaxFanIn: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.TempDir = t.TempDir()
+			if _, err := ReconcileExternal(strings.NewReader(tt.input), tt.opts); err == nil {
+				t.Error("ReconcileExternal() error = nil, want an error")
+			}
+		})
+	}
+}
+
+// TestReconcileExternalManyRuns spills more runs than can be merged at once,
+// so they have to be merged down in passes first.
+func TestReconcileExternalManyRuns(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var sb strings.Builder
+	for range 300 {
+		fmt.Fprintf(&sb, "%d   %d\n", rng.Intn(50), rng.Intn(50))
+	}
+	input := sb.String()
+	want, err := NewReport(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fanIn := range []int{0, 2, 3, 64} {
+		t.Run(fmt.Sprintf("fan-in %d", fanIn), func(t *testing.T) {
+			got, err := ReconcileExternal(strings.NewReader(input), ExternalOptions{
+				RunSize:  1,
+				MaxFanIn: fanIn,
+				TempDir:  t.TempDir(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Entries != 300 || got.TotalDistance != want.TotalDistance || got.SimilarityScore != want.SimilarityScore {
+				t.Errorf("ReconcileExternal() = %+v, want 300 entries, distance %d and similarity %d",
+					got, want.TotalDistance, want.SimilarityScore)
+			}
+		})
+	}
+}
+
+func TestRunSpillerReduce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	dir := t.TempDir()
+	rs := &runSpiller{dir: dir, prefix: "lhs", size: 3}
+	want := make([]entry, 100)
+	for i := range want {
+		want[i] = entry{value: rng.Intn(20), index: i}
+		if err := rs.add(want[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rs.flush(); err != nil {
+		t.Fatal(err)
+	}
+	slices.SortFunc(want, compareEntries)
+
+	const fanIn = 4
+	if err := rs.reduce(fanIn); err != nil {
+		t.Fatal(err)
+	}
+	if len(rs.runs) > fanIn {
+		t.Errorf("%d runs left, want at most %d", len(rs.runs), fanIn)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(rs.runs) {
+		t.Errorf("%d files left in the run directory, want the %d runs", len(files), len(rs.runs))
+	}
+
+	m, err := newRunMerger(rs.runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.close()
+	got := make([]entry, 0, len(want))
+	for {
+		e, ok, err := m.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, e)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("merged runs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package day01
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format is the layout of a file of lists, one list per column.
+type Format int
+
+const (
+	// FormatWhitespace separates columns with any run of spaces or tabs, as the
+	// puzzle input does.
+	FormatWhitespace Format = iota
+	FormatCSV
+	FormatTSV
+)
+
+// ParseFormat maps a format name as given on the command line to a Format.
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "whitespace":
+		return FormatWhitespace, nil
+	case "csv":
+		return FormatCSV, nil
+	case "tsv":
+		return FormatTSV, nil
+	}
+	return 0, fmt.Errorf("unknown format %q, expected whitespace, csv or tsv", name)
+}
+
+// LoadOptions configures LoadLists.
+type LoadOptions struct {
+	Format Format
+	// Header treats the first row as column names rather than entries.
+	Header bool
+}
+
+// Lists holds several lists of the same length side by side.
+type Lists struct {
+	// Names labels each column, either from the header row or by its
+	// position counting from 1.
+	Names   []string
+	Columns [][]int
+}
+
+// LoadLists reads any number of equal length lists laid out in columns.
+func LoadLists(r io.Reader, opts LoadOptions) (Lists, error) {
+	var rows [][]string
+	var err error
+	switch opts.Format {
+	case FormatWhitespace:
+		rows, err = readWhitespaceRows(r)
+	case FormatCSV:
+		rows, err = readDelimitedRows(r, ',')
+	case FormatTSV:
+		rows, err = readDelimitedRows(r, '\t')
+	default:
+		return Lists{}, fmt.Errorf("unknown format %d", opts.Format)
+	}
+	if err != nil {
+		return Lists{}, err
+	}
+	if len(rows) == 0 {
+		return Lists{}, errors.New("no rows found")
+	}
+
+	lists := Lists{}
+	if opts.Header {
+		lists.Names = rows[0]
+		rows = rows[1:]
+	} else {
+		for i := range rows[0] {
+			lists.Names = append(lists.Names, strconv.Itoa(i+1))
+		}
+	}
+	if len(lists.Names) < 2 {
+		return Lists{}, fmt.Errorf("expected at least two columns, got %d", len(lists.Names))
+	}
+
+	// Rows are numbered from 1 in errors, counting the header but not blank
+	// lines.
+	firstRow := 1
+	if opts.Header {
+		firstRow = 2
+	}
+	lists.Columns = make([][]int, len(lists.Names))
+	for rowIndex, row := range rows {
+		if len(row) != len(lists.Names) {
+			return Lists{}, fmt.Errorf("row %d has %d columns, expected %d", rowIndex+firstRow, len(row), len(lists.Names))
+		}
+		for col, field := range row {
+			value, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return Lists{}, fmt.Errorf("row %d column %s: %w", rowIndex+firstRow, lists.Names[col], err)
+			}
+			lists.Columns[col] = append(lists.Columns[col], value)
+		}
+	}
+	return lists, nil
+}
+
+func readWhitespaceRows(r io.Reader) ([][]string, error) {
+	rows := make([][]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			// Skip blank lines.
+			continue
+		}
+		rows = append(rows, fields)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func readDelimitedRows(r io.Reader, delimiter rune) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	// Row lengths are checked by LoadLists, which can name the columns.
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// DistanceMatrix returns the total distance between every pair of columns,
+// where entry [i][j] pairs column i with column j as part one pairs the left
+// and right lists.
+func DistanceMatrix(lists Lists) ([][]int, error) {
+	return columnMatrix(lists, func(lhs []int, rhs []int) (int, error) {
+		return sumDistances(pairLists(lhs, rhs))
+	})
+}
+
+// SimilarityMatrix returns the similarity score between every pair of columns,
+// where entry [i][j] scores column i against column j as part two scores the
+// left list against the right.
+func SimilarityMatrix(lists Lists) ([][]int, error) {
+	return columnMatrix(lists, func(lhs []int, rhs []int) (int, error) {
+		terms, err := similarityTerms(lhs, rhs)
+		if err != nil {
+			return 0, err
+		}
+		return sumSimilarity(terms)
+	})
+}
+
+func columnMatrix(lists Lists, compare func(lhs []int, rhs []int) (int, error)) ([][]int, error) {
+	matrix := make([][]int, len(lists.Columns))
+	for i := range lists.Columns {
+		matrix[i] = make([]int, len(lists.Columns))
+	}
+	// Both comparisons are symmetric, so only the upper triangle is computed.
+	for i := range lists.Columns {
+		for j := i; j < len(lists.Columns); j++ {
+			value, err := compare(lists.Columns[i], lists.Columns[j])
+			if err != nil {
+				return nil, fmt.Errorf("columns %s and %s: %w", lists.Names[i], lists.Names[j], err)
+			}
+			matrix[i][j] = value
+			matrix[j][i] = value
+		}
+	}
+	return matrix, nil
+}
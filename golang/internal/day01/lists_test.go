@@ -0,0 +1,142 @@
+package day01
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadLists(t *testing.T) {
+	want := Lists{
+		Names:   []string{"1", "2", "3"},
+		Columns: [][]int{{3, 4, 2}, {4, 3, 5}, {-1, 0, 10}},
+	}
+	named := Lists{Names: []string{"left", "right", "extra"}, Columns: want.Columns}
+	tests := []struct {
+		name  string
+		input string
+		opts  LoadOptions
+		want  Lists
+	}{
+		{
+			name:  "whitespace",
+			input: "3   4 -1\n\n4\t3  0\n  2 5 10\n",
+			opts:  LoadOptions{Format: FormatWhitespace},
+			want:  want,
+		},
+		{
+			name:  "whitespace with header",
+			input: "left right extra\n3 4 -1\n4 3 0\n2 5 10\n",
+			opts:  LoadOptions{Format: FormatWhitespace, Header: true},
+			want:  named,
+		},
+		{
+			name:  "csv",
+			input: "3,4,-1\n4, 3 ,0\n2,5,\"10\"\n",
+			opts:  LoadOptions{Format: FormatCSV},
+			want:  want,
+		},
+		{
+			name:  "csv with header",
+			input: "left,right,extra\n3,4,-1\n4,3,0\n2,5,10\n",
+			opts:  LoadOptions{Format: FormatCSV, Header: true},
+			want:  named,
+		},
+		{
+			name:  "tsv",
+			input: "3\t4\t-1\n4\t3\t0\n2\t5\t10\n",
+			opts:  LoadOptions{Format: FormatTSV},
+			want:  want,
+		},
+		{
+			name:  "tsv with header",
+			input: "left\tright\textra\n3\t4\t-1\n4\t3\t0\n2\t5\t10\n",
+			opts:  LoadOptions{Format: FormatTSV, Header: true},
+			want:  named,
+		},
+		{
+			name:  "two columns",
+			input: "1 2\n",
+			want:  Lists{Names: []string{"1", "2"}, Columns: [][]int{{1}, {2}}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := LoadLists(strings.NewReader(tc.input), tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("LoadLists() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadListsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  LoadOptions
+		want  string
+	}{
+		{name: "unknown format", input: "1 2\n", opts: LoadOptions{Format: Format(9)}, want: "unknown format 9"},
+		{name: "empty", input: "\n\n", want: "no rows found"},
+		{name: "one column", input: "1\n2\n", want: "expected at least two columns, got 1"},
+		{name: "header only names one column", input: "left\n1 2\n", opts: LoadOptions{Header: true}, want: "expected at least two columns, got 1"},
+		{name: "short row", input: "1 2\n3\n", want: "row 2 has 1 columns, expected 2"},
+		{name: "long row", input: "1,2\n3,4\n5,6,7\n", opts: LoadOptions{Format: FormatCSV}, want: "row 3 has 3 columns, expected 2"},
+		{name: "short row after header", input: "a\tb\n1\t2\n3\n", opts: LoadOptions{Format: FormatTSV, Header: true}, want: "row 3 has 1 columns, expected 2"},
+		{name: "bad field", input: "1 2\n3 x\n", want: `row 2 column 2: strconv.Atoi: parsing "x"`},
+		{name: "bad field after header", input: "a,b\n1,2\nx,4\n", opts: LoadOptions{Format: FormatCSV, Header: true}, want: `row 3 column a: strconv.Atoi: parsing "x"`},
+		{name: "bad csv quoting", input: "1,\"2\n", opts: LoadOptions{Format: FormatCSV}, want: "extraneous or missing"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadLists(strings.NewReader(tc.input), tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("LoadLists() error = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"whitespace": FormatWhitespace, "csv": FormatCSV, "tsv": FormatTSV} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("json"); err == nil {
+		t.Error(`ParseFormat("json") error = nil, want an error`)
+	}
+}
+
+func TestMatrices(t *testing.T) {
+	// The puzzle's two lists, with the left one repeated as a third.
+	lists := Lists{
+		Names: []string{"left", "right", "again"},
+		Columns: [][]int{
+			{3, 4, 2, 1, 3, 3},
+			{4, 3, 5, 3, 9, 3},
+			{3, 4, 2, 1, 3, 3},
+		},
+	}
+	distances, err := DistanceMatrix(lists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDistances := [][]int{{0, 11, 0}, {11, 0, 11}, {0, 11, 0}}
+	if !reflect.DeepEqual(distances, wantDistances) {
+		t.Errorf("DistanceMatrix() = %v, want %v", distances, wantDistances)
+	}
+
+	similarities, err := SimilarityMatrix(lists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSimilarities := [][]int{{34, 31, 34}, {31, 45, 31}, {34, 31, 34}}
+	if !reflect.DeepEqual(similarities, wantSimilarities) {
+		t.Errorf("SimilarityMatrix() = %v, want %v", similarities, wantSimilarities)
+	}
+}
@@ -0,0 +1,105 @@
+package day02
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDampen(t *testing.T) {
+	tests := []struct {
+		levels      []int
+		maxRemovals int
+		wantRemoved []int
+		wantOk      bool
+	}{
+		{levels: []int{7, 6, 4, 2, 1}, maxRemovals: 1, wantRemoved: []int{}, wantOk: true},
+		{levels: []int{1, 2, 7, 8, 9}, maxRemovals: 1, wantOk: false},
+		{levels: []int{1, 3, 2, 4, 5}, maxRemovals: 1, wantRemoved: []int{2}, wantOk: true},
+		{levels: []int{8, 6, 4, 4, 1}, maxRemovals: 1, wantRemoved: []int{3}, wantOk: true},
+		{levels: []int{1, 2, 7, 8, 9}, maxRemovals: 2, wantRemoved: []int{0, 1}, wantOk: true},
+		{levels: []int{5, 1, 2, 9, 3, 4}, maxRemovals: 2, wantRemoved: []int{0, 3}, wantOk: true},
+		{levels: []int{}, maxRemovals: 0, wantRemoved: nil, wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.levels, tt.maxRemovals), func(t *testing.T) {
+			removed, ok := Dampen(tt.levels, tt.maxRemovals)
+			if ok != tt.wantOk || !slices.Equal(removed, tt.wantRemoved) {
+				t.Errorf("Dampen() = %v, %v, want %v, %v", removed, ok, tt.wantRemoved, tt.wantOk)
+			}
+		})
+	}
+}
+
+// fewestRemovalsBruteForce tries every way of removing up to maxRemovals
+// levels, fewest first, and returns how many it took.
+func fewestRemovalsBruteForce(rules RuleSet, levels []int, maxRemovals int) (int, bool) {
+	for count := 0; count <= min(maxRemovals, len(levels)); count++ {
+		for mask := 0; mask < 1<<len(levels); mask++ {
+			if bitCount(mask) != count {
+				continue
+			}
+			if rules.Evaluate(withoutLevels(levels, mask)).Safe {
+				return count, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func bitCount(mask int) int {
+	count := 0
+	for ; mask > 0; mask &= mask - 1 {
+		count += 1
+	}
+	return count
+}
+
+func withoutLevels(levels []int, mask int) []int {
+	kept := make([]int, 0, len(levels))
+	for i, level := range levels {
+		if mask&(1<<i) == 0 {
+			kept = append(kept, level)
+		}
+	}
+	return kept
+}
+
+// TestDampenBruteForce checks the linear time dampener against trying every
+// subset of levels to remove on small random reports.
+func TestDampenBruteForce(t *testing.T) {
+	ruleSets := []RuleSet{
+		DefaultRules,
+		{MinDelta: 1, MaxDelta: 2, Direction: Increasing},
+		{MinDelta: 0, MaxDelta: 4, Direction: Decreasing},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := range 3000 {
+		rules := ruleSets[i%len(ruleSets)]
+		levels := make([]int, rng.Intn(9))
+		for j := range levels {
+			levels[j] = rng.Intn(10)
+		}
+		maxRemovals := rng.Intn(4)
+
+		removed, ok := rules.Dampen(levels, maxRemovals)
+		wantCount, wantOk := fewestRemovalsBruteForce(rules, levels, maxRemovals)
+		if ok != wantOk {
+			t.Fatalf("%+v Dampen(%v, %d) ok = %v, want %v", rules, levels, maxRemovals, ok, wantOk)
+		}
+		if !ok {
+			continue
+		}
+		if len(removed) != wantCount {
+			t.Fatalf("%+v Dampen(%v, %d) removed %v, want %d removal(s)", rules, levels, maxRemovals, removed, wantCount)
+		}
+		mask := 0
+		for _, index := range removed {
+			mask |= 1 << index
+		}
+		if !rules.Evaluate(withoutLevels(levels, mask)).Safe {
+			t.Fatalf("%+v Dampen(%v, %d) removed %v, which leaves an unsafe report", rules, levels, maxRemovals, removed)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package day02 solves day 2, Red-Nosed Reports.
+package day02
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+type levelHandler struct {
+	levelsList [][]int
+}
+
+func (handler *levelHandler) addLevels(levels []int) {
+	handler.levelsList = append(handler.levelsList, levels)
+}
+
+// Dampen finds the fewest levels, up to maxRemovals, that have to be removed
+// for levels to be safe under DefaultRules. It returns their indexes in
+// ascending order, or false if removing maxRemovals levels isn't enough.
+func Dampen(levels []int, maxRemovals int) ([]int, bool) {
+	return DefaultRules.Dampen(levels, maxRemovals)
+}
+
+// Dampen is like the package level Dampen but for any rule set. Tolerances
+// aren't applied, as removed levels already stand in for them. Each allowed
+// direction is tried in O(n * maxRemovals) time.
+func (rules RuleSet) Dampen(levels []int, maxRemovals int) ([]int, bool) {
+	var best []int
+	found := false
+	for _, direction := range rules.directions() {
+		removed, ok := rules.dampenInDirection(levels, maxRemovals, direction)
+		if ok && (!found || len(removed) < len(best)) {
+			best = removed
+			found = true
+		}
+	}
+	return best, found
+}
+
+// dampenInDirection keeps, for each level, the fewest removals needed for a
+// safe run of kept levels ending with it. Only the last kept level matters
+// for what can follow, so the fewest removals always wins. A level can only
+// follow one of the maxRemovals+1 levels before it, as anything further back
+// would need too many levels removed in between.
+func (rules RuleSet) dampenInDirection(levels []int, maxRemovals int, direction int) ([]int, bool) {
+	n := len(levels)
+	if n == 0 {
+		return nil, true
+	}
+
+	fewestRemovals := make([]int, n)
+	previousKept := make([]int, n)
+	for i := range levels {
+		// Keeping i as the first level means removing every level before it.
+		fewestRemovals[i] = i
+		previousKept[i] = -1
+		for j := max(0, i-maxRemovals-1); j < i; j++ {
+			if !rules.allowsStep(levels[j], levels[i], direction) {
+				continue
+			}
+			removals := fewestRemovals[j] + i - j - 1
+			if removals < fewestRemovals[i] {
+				fewestRemovals[i] = removals
+				previousKept[i] = j
+			}
+		}
+	}
+
+	last := -1
+	for i := max(0, n-maxRemovals-1); i < n; i++ {
+		total := fewestRemovals[i] + n - 1 - i
+		if total <= maxRemovals && (last == -1 || total < fewestRemovals[last]+n-1-last) {
+			last = i
+		}
+	}
+	if last == -1 {
+		return nil, false
+	}
+
+	kept := make([]bool, n)
+	for i := last; i != -1; i = previousKept[i] {
+		kept[i] = true
+	}
+	removed := make([]int, 0)
+	for i := range levels {
+		if !kept[i] {
+			removed = append(removed, i)
+		}
+	}
+	return removed, true
+}
+
+func (handler *levelHandler) getSafeCount() int {
+	count := 0
+	for _, levels := range handler.levelsList {
+		if DefaultRules.Evaluate(levels).Safe {
+			count += 1
+		}
+	}
+
+	return count
+}
+
+func (handler *levelHandler) getSafeCountWithModulator() int {
+	count := 0
+	for _, levels := range handler.levelsList {
+		if _, ok := Dampen(levels, 1); ok {
+			count += 1
+		}
+	}
+
+	return count
+}
+
+func handleLine(line string, handler *levelHandler) error {
+	tokens := strings.Split(line, " ")
+	levels := make([]int, len(tokens))
+	for i, token := range tokens {
+		reading, err := strconv.Atoi(token)
+		if err != nil {
+			return err
+		}
+
+		levels[i] = reading
+	}
+	handler.addLevels(levels)
+	return nil
+}
+
+func parseInput(input string) (levelHandler, error) {
+	handler := levelHandler{
+		make([][]int, 0),
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			// Skip blank lines.
+			continue
+		}
+		if err := handleLine(scanner.Text(), &handler); err != nil {
+			return levelHandler{}, err
+		}
+	}
+	return handler, nil
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	handler, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(handler.getSafeCount()), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	handler, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(handler.getSafeCountWithModulator()), nil
+}
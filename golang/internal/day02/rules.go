@@ -0,0 +1,177 @@
+package day02
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Direction is which way the levels of a safe report may move.
+type Direction string
+
+const (
+	Increasing Direction = "increasing"
+	Decreasing Direction = "decreasing"
+	// Either lets a report go either way, as long as it keeps going the way
+	// its first step went.
+	Either Direction = "either"
+)
+
+// The rules a report can break, as named in verdicts and tolerances.
+const (
+	RuleMinDelta  = "min_delta"
+	RuleMaxDelta  = "max_delta"
+	RuleDirection = "direction"
+)
+
+// RuleSet is what makes a report safe. The puzzle's rules are DefaultRules.
+type RuleSet struct {
+	// MinDelta and MaxDelta bound how far apart adjacent levels may be.
+	MinDelta  int       `json:"min_delta"`
+	MaxDelta  int       `json:"max_delta"`
+	Direction Direction `json:"direction"`
+	// Tolerance is how many steps may break each rule, keyed by rule name,
+	// before the report counts as unsafe. Rules left out tolerate nothing.
+	Tolerance map[string]int `json:"tolerance,omitempty"`
+}
+
+// DefaultRules are the puzzle's rules: levels move by 1 to 3 at a time, all
+// in the same direction.
+var DefaultRules = RuleSet{MinDelta: 1, MaxDelta: 3, Direction: Either}
+
+// LoadRuleSet reads a rule set from JSON, such as
+// {"min_delta": 1, "max_delta": 5, "direction": "increasing", "tolerance": {"max_delta": 1}}.
+func LoadRuleSet(r io.Reader) (RuleSet, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var rules RuleSet
+	if err := decoder.Decode(&rules); err != nil {
+		return RuleSet{}, fmt.Errorf("reading rule set: %w", err)
+	}
+	if err := rules.validate(); err != nil {
+		return RuleSet{}, err
+	}
+	return rules, nil
+}
+
+func (rules RuleSet) validate() error {
+	if rules.MinDelta < 0 {
+		return fmt.Errorf("min_delta must not be negative, got %d", rules.MinDelta)
+	}
+	if rules.MaxDelta < rules.MinDelta {
+		return fmt.Errorf("max_delta %d is less than min_delta %d", rules.MaxDelta, rules.MinDelta)
+	}
+	switch rules.Direction {
+	case Increasing, Decreasing, Either:
+	default:
+		return fmt.Errorf("unknown direction %q, expected %s, %s or %s", rules.Direction, Increasing, Decreasing, Either)
+	}
+	for name, tolerance := range rules.Tolerance {
+		switch name {
+		case RuleMinDelta, RuleMaxDelta, RuleDirection:
+		default:
+			return fmt.Errorf("tolerance given for unknown rule %q", name)
+		}
+		if tolerance < 0 {
+			return fmt.Errorf("tolerance for %s must not be negative, got %d", name, tolerance)
+		}
+	}
+	return nil
+}
+
+// Verdict is the outcome of checking one report against a rule set.
+type Verdict struct {
+	Safe bool
+	// Rule is the first rule broken more often than its tolerance allows, and
+	// Index the level that broke it. Both are unset for a safe report.
+	Rule   string
+	Index  int
+	Reason string
+}
+
+// Evaluate checks levels against the rules, stopping at the first rule that
+// runs out of tolerance.
+func (rules RuleSet) Evaluate(levels []int) Verdict {
+	violations := make(map[string]int)
+	direction := rules.Direction
+	for i := 1; i < len(levels); i++ {
+		step := levels[i] - levels[i-1]
+		distance := max(step, -step)
+
+		if direction == Either && step != 0 {
+			direction = Increasing
+			if step < 0 {
+				direction = Decreasing
+			}
+		}
+
+		var rule, reason string
+		switch {
+		case distance < rules.MinDelta:
+			rule = RuleMinDelta
+			reason = fmt.Sprintf("%d to %d moves by %d, less than %d", levels[i-1], levels[i], distance, rules.MinDelta)
+		case distance > rules.MaxDelta:
+			rule = RuleMaxDelta
+			reason = fmt.Sprintf("%d to %d moves by %d, more than %d", levels[i-1], levels[i], distance, rules.MaxDelta)
+		case (direction == Increasing && step < 0) || (direction == Decreasing && step > 0):
+			rule = RuleDirection
+			reason = fmt.Sprintf("%d to %d breaks the %s direction", levels[i-1], levels[i], direction)
+		default:
+			continue
+		}
+
+		violations[rule] += 1
+		if violations[rule] > rules.Tolerance[rule] {
+			return Verdict{Rule: rule, Index: i, Reason: reason}
+		}
+	}
+	return Verdict{Safe: true}
+}
+
+// directions returns the ways levels may move, as +1 for increasing and -1
+// for decreasing.
+func (rules RuleSet) directions() []int {
+	switch rules.Direction {
+	case Increasing:
+		return []int{1}
+	case Decreasing:
+		return []int{-1}
+	}
+	return []int{1, -1}
+}
+
+// allowsStep reports whether moving from prev to next is within the deltas
+// going in direction.
+func (rules RuleSet) allowsStep(prev int, next int, direction int) bool {
+	step := (next - prev) * direction
+	return step >= rules.MinDelta && step <= rules.MaxDelta
+}
+
+// UnsafeReport is a report that failed its rule set.
+type UnsafeReport struct {
+	// Number counts reports from 1 in the order they appear in the input.
+	Number  int
+	Levels  []int
+	Verdict Verdict
+}
+
+// FindUnsafeReports checks every report in input and returns the ones that
+// aren't safe under rules, with the reason why.
+func FindUnsafeReports(input string, rules RuleSet) ([]UnsafeReport, error) {
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+	handler, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	unsafe := make([]UnsafeReport, 0)
+	for i, levels := range handler.levelsList {
+		verdict := rules.Evaluate(levels)
+		if !verdict.Safe {
+			unsafe = append(unsafe, UnsafeReport{Number: i + 1, Levels: levels, Verdict: verdict})
+		}
+	}
+	return unsafe, nil
+}
@@ -0,0 +1,199 @@
+package day02
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     RuleSet
+		levels    []int
+		wantRule  string
+		wantIndex int
+	}{
+		{name: "safe decreasing", rules: DefaultRules, levels: []int{7, 6, 4, 2, 1}},
+		{name: "safe increasing", rules: DefaultRules, levels: []int{1, 3, 6, 7, 9}},
+		{name: "empty", rules: DefaultRules, levels: []int{}},
+		{name: "single level", rules: DefaultRules, levels: []int{5}},
+		{name: "too small a step", rules: DefaultRules, levels: []int{8, 6, 4, 4, 1}, wantRule: RuleMinDelta, wantIndex: 3},
+		{name: "too big a step", rules: DefaultRules, levels: []int{1, 2, 7, 8, 9}, wantRule: RuleMaxDelta, wantIndex: 2},
+		{name: "turns around", rules: DefaultRules, levels: []int{1, 3, 2, 4, 5}, wantRule: RuleDirection, wantIndex: 2},
+		{
+			name:      "increasing only",
+			rules:     RuleSet{MinDelta: 1, MaxDelta: 3, Direction: Increasing},
+			levels:    []int{5, 4, 5},
+			wantRule:  RuleDirection,
+			wantIndex: 1,
+		},
+		{
+			name:      "decreasing only",
+			rules:     RuleSet{MinDelta: 1, MaxDelta: 3, Direction: Decreasing},
+			levels:    []int{5, 4, 5},
+			wantRule:  RuleDirection,
+			wantIndex: 2,
+		},
+		{
+			name:   "either waits for a step that moves",
+			rules:  RuleSet{MinDelta: 0, MaxDelta: 3, Direction: Either},
+			levels: []int{5, 5, 3, 3, 1},
+		},
+		{
+			name:      "either keeps the first step's direction",
+			rules:     RuleSet{MinDelta: 0, MaxDelta: 3, Direction: Either},
+			levels:    []int{5, 5, 6, 5},
+			wantRule:  RuleDirection,
+			wantIndex: 3,
+		},
+		{
+			name:   "within tolerance",
+			rules:  RuleSet{MinDelta: 1, MaxDelta: 3, Direction: Either, Tolerance: map[string]int{RuleMaxDelta: 1}},
+			levels: []int{1, 2, 7, 8, 9},
+		},
+		{
+			name:      "past tolerance",
+			rules:     RuleSet{MinDelta: 1, MaxDelta: 3, Direction: Either, Tolerance: map[string]int{RuleMaxDelta: 1}},
+			levels:    []int{1, 2, 7, 8, 20},
+			wantRule:  RuleMaxDelta,
+			wantIndex: 4,
+		},
+		{
+			name:      "tolerance is per rule",
+			rules:     RuleSet{MinDelta: 1, MaxDelta: 3, Direction: Either, Tolerance: map[string]int{RuleMaxDelta: 1}},
+			levels:    []int{1, 2, 7, 7},
+			wantRule:  RuleMinDelta,
+			wantIndex: 3,
+		},
+		{
+			name:   "tolerating direction changes",
+			rules:  RuleSet{MinDelta: 1, MaxDelta: 3, Direction: Either, Tolerance: map[string]int{RuleDirection: 2}},
+			levels: []int{1, 3, 2, 4, 3, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := tt.rules.Evaluate(tt.levels)
+			wantSafe := tt.wantRule == ""
+			if verdict.Safe != wantSafe || verdict.Rule != tt.wantRule || verdict.Index != tt.wantIndex {
+				t.Errorf("Evaluate(%v) = %+v, want rule %q at index %d", tt.levels, verdict, tt.wantRule, tt.wantIndex)
+			}
+			if verdict.Safe != (verdict.Reason == "") {
+				t.Errorf("Evaluate(%v) reason = %q, want a reason only for unsafe reports", tt.levels, verdict.Reason)
+			}
+		})
+	}
+}
+
+func TestLoadRuleSet(t *testing.T) {
+	input := `{"min_delta": 1, "max_delta": 5, "direction": "increasing", "tolerance": {"max_delta": 1}}`
+	rules, err := LoadRuleSet(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadRuleSet() error = %v", err)
+	}
+	if rules.MinDelta != 1 || rules.MaxDelta != 5 || rules.Direction != Increasing ||
+		len(rules.Tolerance) != 1 || rules.Tolerance[RuleMaxDelta] != 1 {
+		t.Errorf("LoadRuleSet() = %+v", rules)
+	}
+}
+
+func TestLoadRuleSetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "not json", input: `min_delta: 1`, wantErr: "reading rule set"},
+		{name: "unknown field", input: `{"min_delta": 1, "max_delta": 3, "direction": "either", "slope": 2}`, wantErr: "unknown field"},
+		{name: "negative min", input: `{"min_delta": -1, "max_delta": 3, "direction": "either"}`, wantErr: "min_delta must not be negative"},
+		{name: "max below min", input: `{"min_delta": 3, "max_delta": 1, "direction": "either"}`, wantErr: "max_delta 1 is less than min_delta 3"},
+		{name: "missing direction", input: `{"min_delta": 1, "max_delta": 3}`, wantErr: `unknown direction ""`},
+		{name: "unknown direction", input: `{"min_delta": 1, "max_delta": 3, "direction": "sideways"}`, wantErr: `unknown direction "sideways"`},
+		{
+			name:    "unknown tolerance",
+			input:   `{"min_delta": 1, "max_delta": 3, "direction": "either", "tolerance": {"slope": 1}}`,
+			wantErr: `tolerance given for unknown rule "slope"`,
+		},
+		{
+			name:    "negative tolerance",
+			input:   `{"min_delta": 1, "max_delta": 3, "direction": "either", "tolerance": {"direction": -1}}`,
+			wantErr: "tolerance for direction must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRuleSet(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadRuleSet() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindUnsafeReports(t *testing.T) {
+	input, err := os.ReadFile("testdata/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsafe, err := FindUnsafeReports(string(input), DefaultRules)
+	if err != nil {
+		t.Fatalf("FindUnsafeReports() error = %v", err)
+	}
+	want := []struct {
+		number int
+		rule   string
+		index  int
+	}{
+		{2, RuleMaxDelta, 2},
+		{3, RuleMaxDelta, 3},
+		{4, RuleDirection, 2},
+		{5, RuleMinDelta, 3},
+	}
+	if len(unsafe) != len(want) {
+		t.Fatalf("FindUnsafeReports() found %d unsafe reports, want %d: %+v", len(unsafe), len(want), unsafe)
+	}
+	for i, w := range want {
+		got := unsafe[i]
+		if got.Number != w.number || got.Verdict.Rule != w.rule || got.Verdict.Index != w.index {
+			t.Errorf("unsafe report %d = %+v, want report %d breaking %s at index %d", i, got, w.number, w.rule, w.index)
+		}
+	}
+
+	if _, err := FindUnsafeReports(string(input), RuleSet{MinDelta: 1, MaxDelta: 3}); err == nil {
+		t.Error("FindUnsafeReports() with no direction error = nil, want an error")
+	}
+}
+
+// TestFindUnsafeReportsMatchesDampen checks that a report is unsafe under the
+// puzzle's rules exactly when Dampen can't make it safe without removals.
+func TestFindUnsafeReportsMatchesDampen(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	reports := make([][]int, 500)
+	var input strings.Builder
+	for i := range reports {
+		levels := make([]int, 1+rng.Intn(7))
+		for j := range levels {
+			levels[j] = rng.Intn(10)
+		}
+		reports[i] = levels
+		input.WriteString(strings.Trim(fmt.Sprint(levels), "[]") + "\n")
+	}
+
+	unsafe, err := FindUnsafeReports(input.String(), DefaultRules)
+	if err != nil {
+		t.Fatalf("FindUnsafeReports() error = %v", err)
+	}
+	found := make(map[int]bool)
+	for _, report := range unsafe {
+		found[report.Number] = true
+	}
+	for i, levels := range reports {
+		_, safe := Dampen(levels, 0)
+		if found[i+1] == safe {
+			t.Errorf("report %d %v: FindUnsafeReports unsafe = %v, Dampen safe = %v", i+1, levels, found[i+1], safe)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package day03 solves day 3, Mull It Over.
+package day03
+
+import (
+	"strconv"
+)
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	state, _, err := NewPuzzleInterpreter().Run(input)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(state.Sum), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	state, _, err := NewPuzzleInterpreter().Run(input)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(state.EnabledSum), nil
+}
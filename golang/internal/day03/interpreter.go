@@ -0,0 +1,324 @@
+package day03
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+// State is what a program's instructions act on.
+type State struct {
+	Enabled bool
+	// Sum totals every result, and EnabledSum only those produced while
+	// Enabled.
+	Sum        int
+	EnabledSum int
+}
+
+// DisabledSum totals the results produced while not Enabled.
+func (s State) DisabledSum() int {
+	return s.Sum - s.EnabledSum
+}
+
+// Accumulate adds an instruction's result to the sums.
+func (s *State) Accumulate(value int) error {
+	sum, ok := checked.Add(s.Sum, value)
+	if !ok {
+		return fmt.Errorf("%w: sum of results", checked.ErrOverflow)
+	}
+	s.Sum = sum
+	if !s.Enabled {
+		return nil
+	}
+	enabledSum, ok := checked.Add(s.EnabledSum, value)
+	if !ok {
+		return fmt.Errorf("%w: sum of enabled results", checked.ErrOverflow)
+	}
+	s.EnabledSum = enabledSum
+	return nil
+}
+
+// Instruction is one call the interpreter recognises, written as its name
+// followed by Args comma separated numbers in brackets, such as mul(2,4).
+type Instruction struct {
+	Name string
+	Args int
+	// MaxDigits is the most digits each number may have. Zero means no limit.
+	MaxDigits int
+	Exec      func(state *State, args []int) error
+}
+
+// The puzzle's instructions.
+var (
+	Mul = Instruction{Name: "mul", Args: 2, MaxDigits: 3, Exec: func(state *State, args []int) error {
+		product, ok := checked.Mul(args[0], args[1])
+		if !ok {
+			return fmt.Errorf("%w: mul(%d,%d)", checked.ErrOverflow, args[0], args[1])
+		}
+		return state.Accumulate(product)
+	}}
+	Do = Instruction{Name: "do", Exec: func(state *State, _ []int) error {
+		state.Enabled = true
+		return nil
+	}}
+	Dont = Instruction{Name: "don't", Exec: func(state *State, _ []int) error {
+		state.Enabled = false
+		return nil
+	}}
+)
+
+// Extra instructions for scraping commands out of logs.
+var (
+	Add = Instruction{Name: "add", Args: 2, MaxDigits: 3, Exec: func(state *State, args []int) error {
+		return state.Accumulate(args[0] + args[1])
+	}}
+	Sub = Instruction{Name: "sub", Args: 2, MaxDigits: 3, Exec: func(state *State, args []int) error {
+		return state.Accumulate(args[0] - args[1])
+	}}
+	// Reset zeroes both sums.
+	Reset = Instruction{Name: "reset", Exec: func(state *State, _ []int) error {
+		state.Sum = 0
+		state.EnabledSum = 0
+		return nil
+	}}
+)
+
+// Builtins lists every instruction defined here, for looking them up by name.
+var Builtins = []Instruction{Mul, Do, Dont, Add, Sub, Reset}
+
+// Step is one instruction the interpreter ran, along with the state it left
+// behind.
+type Step struct {
+	// Offset and End are the byte range of the instruction in the source.
+	Offset int
+	End    int
+	// Text is the instruction as written, leaving out any line breaks it was
+	// split over.
+	Text  string
+	Name  string
+	Args  []int
+	State State
+}
+
+// Interpreter runs the instructions registered with it, skipping over
+// everything else as corruption.
+type Interpreter struct {
+	instructions map[string]Instruction
+	names        nameTable
+}
+
+// NewInterpreter returns an interpreter that knows instructions.
+func NewInterpreter(instructions ...Instruction) (*Interpreter, error) {
+	in := &Interpreter{instructions: make(map[string]Instruction)}
+	for _, instruction := range instructions {
+		if err := in.Register(instruction); err != nil {
+			return nil, err
+		}
+	}
+	return in, nil
+}
+
+// NewPuzzleInterpreter returns an interpreter for the puzzle's mul, do and
+// don't.
+func NewPuzzleInterpreter() *Interpreter {
+	in, err := NewInterpreter(Mul, Do, Dont)
+	if err != nil {
+		panic(err)
+	}
+	return in
+}
+
+// Register teaches the interpreter another instruction.
+func (in *Interpreter) Register(instruction Instruction) error {
+	if instruction.Name == "" {
+		return errors.New("instruction has no name")
+	}
+	if strings.ContainsAny(instruction.Name, "(),0123456789") {
+		return fmt.Errorf("instruction name %q can't contain brackets, commas or digits", instruction.Name)
+	}
+	if instruction.Args < 0 || instruction.MaxDigits < 0 {
+		return fmt.Errorf("instruction %s has a negative argument count or digit limit", instruction.Name)
+	}
+	if instruction.Exec == nil {
+		return fmt.Errorf("instruction %s has nothing to execute", instruction.Name)
+	}
+	if _, ok := in.instructions[instruction.Name]; ok {
+		return fmt.Errorf("instruction %s is already registered", instruction.Name)
+	}
+	in.instructions[instruction.Name] = instruction
+	in.names.add(instruction.Name)
+	return nil
+}
+
+// readChunkSize is how much RunReader reads at a time.
+const readChunkSize = 64 << 10
+
+// Run executes every well formed instruction in src, starting enabled. It
+// returns the final state and a step for each instruction run.
+func (in *Interpreter) Run(src string) (State, []Step, error) {
+	state := State{Enabled: true}
+	steps := make([]Step, 0)
+	_, err := in.runChunk(newLexer(src, &in.names, true), 0, &state, func(step Step) error {
+		steps = append(steps, step)
+		return nil
+	})
+	if err != nil {
+		return State{}, nil, err
+	}
+	return state, steps, nil
+}
+
+// RunReader is Run for input too large to hold in memory. Instead of
+// collecting steps it calls onStep, if set, as each instruction runs, so the
+// sums can be followed as they grow. Instructions split across reads are
+// carried over to the next one.
+func (in *Interpreter) RunReader(r io.Reader, onStep func(Step) error) (State, error) {
+	if onStep == nil {
+		onStep = func(Step) error { return nil }
+	}
+	state := State{Enabled: true}
+	chunk := make([]byte, readChunkSize)
+	pending := make([]byte, 0, readChunkSize)
+	base := 0
+	for {
+		n, readErr := r.Read(chunk)
+		final := errors.Is(readErr, io.EOF)
+		if readErr != nil && !final {
+			return State{}, readErr
+		}
+		pending = append(pending, chunk[:n]...)
+
+		carryFrom, err := in.runChunk(newLexer(string(pending), &in.names, final), base, &state, onStep)
+		if err != nil {
+			return State{}, err
+		}
+		if final {
+			return state, nil
+		}
+		base += carryFrom
+		pending = append(pending[:0], pending[carryFrom:]...)
+	}
+}
+
+// runChunk executes the instructions in the lexer's source, which starts base
+// bytes into the input. If more input is to come, it stops at the first
+// instruction that might still be completed and returns its offset so that
+// the caller can carry the rest over.
+func (in *Interpreter) runChunk(l *lexer, base int, state *State, onStep func(Step) error) (int, error) {
+	for {
+		tok := l.next()
+		switch tok.kind {
+		case tokenEOF:
+			return l.pos, nil
+		case tokenIncomplete:
+			return tok.offset, nil
+		case tokenName:
+		default:
+			continue
+		}
+
+		instruction := in.instructions[tok.text]
+		args, result := in.parseArgs(l, instruction)
+		switch result {
+		case argsIncomplete:
+			return tok.offset, nil
+		case argsInvalid:
+			// Not an instruction after all. Rescan from just after where the
+			// name started, as the bytes taken for arguments may hold one.
+			l.pos = tok.offset + 1
+			continue
+		}
+		if err := instruction.Exec(state, args); err != nil {
+			return 0, fmt.Errorf("offset %d: %w", base+tok.offset, err)
+		}
+		step := Step{
+			Offset: base + tok.offset,
+			End:    base + l.pos,
+			Text:   withoutLineBreaks(l.src[tok.offset:l.pos]),
+			Name:   tok.text,
+			Args:   args,
+			State:  *state,
+		}
+		if err := onStep(step); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func withoutLineBreaks(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+type argsResult int
+
+const (
+	argsOK argsResult = iota
+	argsInvalid
+	// argsIncomplete means the chunk ended before the arguments did.
+	argsIncomplete
+)
+
+// parseArgs reads the bracketed arguments following an instruction's name
+// and checks them against the instruction's rules.
+func (in *Interpreter) parseArgs(l *lexer, instruction Instruction) ([]int, argsResult) {
+	expect := func(kind tokenKind) (token, argsResult) {
+		tok := l.next()
+		if tok.kind == tokenIncomplete {
+			if tok.text == "" {
+				return tok, argsIncomplete
+			}
+			// Only a number can carry on into the next chunk and still be
+			// what's expected, unless it's already too long.
+			if kind != tokenNumber || !isDigit(tok.text[0]) {
+				return tok, argsInvalid
+			}
+			if instruction.MaxDigits > 0 && len(tok.text) > instruction.MaxDigits {
+				return tok, argsInvalid
+			}
+			return tok, argsIncomplete
+		}
+		if tok.kind != kind {
+			return tok, argsInvalid
+		}
+		return tok, argsOK
+	}
+
+	if _, result := expect(tokenOpen); result != argsOK {
+		return nil, result
+	}
+	args := make([]int, 0, instruction.Args)
+	for i := 0; i < instruction.Args; i++ {
+		if i > 0 {
+			if _, result := expect(tokenComma); result != argsOK {
+				return nil, result
+			}
+		}
+		tok, result := expect(tokenNumber)
+		if result != argsOK {
+			return nil, result
+		}
+		if instruction.MaxDigits > 0 && len(tok.text) > instruction.MaxDigits {
+			return nil, argsInvalid
+		}
+		arg, err := strconv.Atoi(tok.text)
+		if err != nil {
+			// Only possible for numbers too long for an int, which can't be a
+			// valid argument either.
+			return nil, argsInvalid
+		}
+		args = append(args, arg)
+	}
+	if _, result := expect(tokenClose); result != argsOK {
+		return nil, result
+	}
+	return args, argsOK
+}
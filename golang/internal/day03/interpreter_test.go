@@ -0,0 +1,149 @@
+package day03
+
+import (
+	"io"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// chunkReader returns at most one of sizes bytes from each read, cycling
+// through them, so that instructions land across read boundaries.
+type chunkReader struct {
+	r     io.Reader
+	sizes []int
+	read  int
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	size := c.sizes[c.read%len(c.sizes)]
+	c.read += 1
+	return c.r.Read(p[:min(len(p), size)])
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		src          string
+		wantTexts    []string
+		wantOffsets  []int
+		wantSum      int
+		wantEnabled  int
+		instructions []Instruction
+	}{
+		{
+			name:        "puzzle example",
+			src:         "xmul(2,4)&mul[3,7]!^don't()_mul(5,5)+mul(32,64](mul(11,8)undo()?mul(8,5))",
+			wantTexts:   []string{"mul(2,4)", "don't()", "mul(5,5)", "mul(11,8)", "do()", "mul(8,5)"},
+			wantOffsets: []int{1, 20, 28, 48, 59, 64},
+			wantSum:     161,
+			wantEnabled: 48,
+		},
+		{
+			name:        "name split over a line break",
+			src:         "xmu\nl(2,3)",
+			wantTexts:   []string{"mul(2,3)"},
+			wantOffsets: []int{1},
+			wantSum:     6,
+			wantEnabled: 6,
+		},
+		{
+			name:        "number and arguments split over line breaks",
+			src:         "mul(1\r\n2,\n3)don\n't\n()mul(4,5)",
+			wantTexts:   []string{"mul(12,3)", "don't()", "mul(4,5)"},
+			wantOffsets: []int{0, 12, 21},
+			wantSum:     56,
+			wantEnabled: 36,
+		},
+		{
+			name:        "line breaks don't make numbers too long",
+			src:         "mul(12\n34,1)mul(1,2)",
+			wantTexts:   []string{"mul(1,2)"},
+			wantOffsets: []int{12},
+			wantSum:     2,
+			wantEnabled: 2,
+		},
+		{
+			name:         "extra instructions",
+			src:          "add(2,3)sub(1,4)reset()add(5,5)",
+			wantTexts:    []string{"add(2,3)", "sub(1,4)", "reset()", "add(5,5)"},
+			wantOffsets:  []int{0, 8, 16, 23},
+			wantSum:      10,
+			wantEnabled:  10,
+			instructions: []Instruction{Add, Sub, Reset},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := NewPuzzleInterpreter()
+			if tt.instructions != nil {
+				var err error
+				if in, err = NewInterpreter(tt.instructions...); err != nil {
+					t.Fatal(err)
+				}
+			}
+			state, steps, err := in.Run(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			texts := make([]string, len(steps))
+			offsets := make([]int, len(steps))
+			for i, step := range steps {
+				texts[i] = step.Text
+				offsets[i] = step.Offset
+			}
+			if !slices.Equal(texts, tt.wantTexts) || !slices.Equal(offsets, tt.wantOffsets) {
+				t.Errorf("Run() ran %q at %v, want %q at %v", texts, offsets, tt.wantTexts, tt.wantOffsets)
+			}
+			if state.Sum != tt.wantSum || state.EnabledSum != tt.wantEnabled {
+				t.Errorf("Run() sums = %d, %d, want %d, %d", state.Sum, state.EnabledSum, tt.wantSum, tt.wantEnabled)
+			}
+		})
+	}
+}
+
+// TestRunReaderSplits checks that streaming gives the same steps as running
+// the whole source at once however the reads split it, on sources full of
+// instructions broken over lines.
+func TestRunReaderSplits(t *testing.T) {
+	pieces := []string{"mul(", "mu", "l", "(", "12", "3", ",", "4", ")", "do()", "don't()", "do", "n't", "()", "\n", "\r\n", "x", "%"}
+	rng := rand.New(rand.NewSource(1))
+	for range 300 {
+		var sb strings.Builder
+		for range rng.Intn(60) {
+			sb.WriteString(pieces[rng.Intn(len(pieces))])
+		}
+		src := sb.String()
+
+		in := NewPuzzleInterpreter()
+		wantState, wantSteps, err := in.Run(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readers := map[string]io.Reader{
+			"one byte": iotest.OneByteReader(strings.NewReader(src)),
+			"chunks":   &chunkReader{r: strings.NewReader(src), sizes: []int{1 + rng.Intn(7), 1 + rng.Intn(7), 1 + rng.Intn(7)}},
+			"whole":    strings.NewReader(src),
+		}
+		for name, r := range readers {
+			steps := make([]Step, 0)
+			state, err := in.RunReader(r, func(step Step) error {
+				steps = append(steps, step)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("%s: %q: %v", name, src, err)
+			}
+			if state != wantState || !slices.EqualFunc(steps, wantSteps, equalSteps) {
+				t.Fatalf("%s: RunReader(%q) = %+v %+v, want %+v %+v", name, src, state, steps, wantState, wantSteps)
+			}
+		}
+	}
+}
+
+func equalSteps(a Step, b Step) bool {
+	return a.Offset == b.Offset && a.End == b.End && a.Text == b.Text && a.Name == b.Name &&
+		slices.Equal(a.Args, b.Args) && a.State == b.State
+}
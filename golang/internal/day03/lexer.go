@@ -0,0 +1,186 @@
+package day03
+
+import "strings"
+
+// tokenKind is what a token is. Anything the language doesn't use is junk, one
+// byte at a time.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenNumber
+	tokenOpen
+	tokenClose
+	tokenComma
+	tokenJunk
+	// tokenIncomplete runs to the end of a chunk with more input to come, so
+	// what it is can't be known yet.
+	tokenIncomplete
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// lexer splits corrupted memory into tokens. Names are only recognised when
+// they spell a registered instruction, so that the "mul" in "xmul(2,4)" is
+// still found. pos can be moved back to rescan after a failed instruction.
+//
+// Line breaks are transparent: memory is one long stream that only happens to
+// be written out over several lines, so "mu\nl(2,3)" is an instruction and
+// "1\n2" the number 12. A token's text leaves its line breaks out.
+//
+// src may be one chunk of a longer stream, in which case final is false until
+// the last chunk, and tokens that could carry on into the next chunk come back
+// as tokenIncomplete.
+type lexer struct {
+	src   string
+	pos   int
+	final bool
+	names *nameTable
+}
+
+func newLexer(src string, names *nameTable, final bool) *lexer {
+	return &lexer{src: src, names: names, final: final}
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isLineBreak(b byte) bool {
+	return b == '\n' || b == '\r'
+}
+
+// skipLineBreaks returns the first position from pos that isn't a line break.
+func (l *lexer) skipLineBreaks(pos int) int {
+	for pos < len(l.src) && isLineBreak(l.src[pos]) {
+		pos += 1
+	}
+	return pos
+}
+
+// next returns the token at pos and moves past it.
+func (l *lexer) next() token {
+	l.pos = l.skipLineBreaks(l.pos)
+	if l.pos >= len(l.src) {
+		if !l.final {
+			return token{kind: tokenIncomplete, offset: l.pos}
+		}
+		return token{kind: tokenEOF, offset: l.pos}
+	}
+
+	start := l.pos
+	switch b := l.src[start]; {
+	case b == '(':
+		l.pos += 1
+		return token{kind: tokenOpen, text: "(", offset: start}
+	case b == ')':
+		l.pos += 1
+		return token{kind: tokenClose, text: ")", offset: start}
+	case b == ',':
+		l.pos += 1
+		return token{kind: tokenComma, text: ",", offset: start}
+	case isDigit(b):
+		var digits strings.Builder
+		for {
+			end := l.pos
+			for end < len(l.src) && isDigit(l.src[end]) {
+				end += 1
+			}
+			digits.WriteString(l.src[l.pos:end])
+			l.pos = end
+			next := l.skipLineBreaks(end)
+			if next == len(l.src) && !l.final {
+				return token{kind: tokenIncomplete, text: digits.String(), offset: start}
+			}
+			if next == end || next == len(l.src) || !isDigit(l.src[next]) {
+				return token{kind: tokenNumber, text: digits.String(), offset: start}
+			}
+			l.pos = next
+		}
+	}
+
+	// A longer name may still be completed by the next chunk even if a
+	// shorter one already matches, such as "don" of "don't" after "do".
+	if !l.final && l.names.prefixAt(l, start) {
+		l.pos = len(l.src)
+		return token{kind: tokenIncomplete, text: l.src[start:], offset: start}
+	}
+	if name, end, ok := l.names.longestAt(l, start); ok {
+		l.pos = end
+		return token{kind: tokenName, text: name, offset: start}
+	}
+	l.pos += 1
+	return token{kind: tokenJunk, text: l.src[start:l.pos], offset: start}
+}
+
+// nameTable finds registered instruction names in the source, indexed by
+// first byte so that most junk is rejected with a single lookup.
+type nameTable struct {
+	byFirstByte map[byte][]string
+}
+
+func (nt *nameTable) add(name string) {
+	if nt.byFirstByte == nil {
+		nt.byFirstByte = make(map[byte][]string)
+	}
+	names := append(nt.byFirstByte[name[0]], name)
+	// Longest first, so "don't" wins over "do".
+	for i := len(names) - 1; i > 0 && len(names[i]) > len(names[i-1]); i-- {
+		names[i], names[i-1] = names[i-1], names[i]
+	}
+	nt.byFirstByte[name[0]] = names
+}
+
+type nameMatch int
+
+const (
+	nameMismatch nameMatch = iota
+	nameMatched
+	// namePrefix means the source ran out part way through the name.
+	namePrefix
+)
+
+// matchName compares name to the lexer's source from pos, skipping line
+// breaks, and returns where the match ended.
+func (l *lexer) matchName(name string, pos int) (int, nameMatch) {
+	for i := range len(name) {
+		if i > 0 {
+			pos = l.skipLineBreaks(pos)
+		}
+		if pos == len(l.src) {
+			return pos, namePrefix
+		}
+		if l.src[pos] != name[i] {
+			return pos, nameMismatch
+		}
+		pos += 1
+	}
+	return pos, nameMatched
+}
+
+// longestAt returns the longest name at pos in the lexer's source and the
+// position just past it.
+func (nt *nameTable) longestAt(l *lexer, pos int) (string, int, bool) {
+	for _, name := range nt.byFirstByte[l.src[pos]] {
+		if end, match := l.matchName(name, pos); match == nameMatched {
+			return name, end, true
+		}
+	}
+	return "", 0, false
+}
+
+// prefixAt reports whether the rest of the lexer's source from pos is the
+// start of a name too long to fit.
+func (nt *nameTable) prefixAt(l *lexer, pos int) bool {
+	for _, name := range nt.byFirstByte[l.src[pos]] {
+		if _, match := l.matchName(name, pos); match == namePrefix {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+// Package day04 solves day 4, Ceres Search.
+package day04
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+// xMasKernel is two MAS crossing at the A. Its rotations cover every way
+// round the two words can be written.
+var xMasKernel = mustParseKernel("M.S/.A./M.S")
+
+func parseInput(input string) []string {
+	grid := make([]string, 0)
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			// Skip blank lines.
+			continue
+		}
+		grid = append(grid, scanner.Text())
+	}
+	return grid
+}
+
+// ParseGrid reads a word search grid, one row per line.
+func ParseGrid(input string) ([]string, error) {
+	grid := parseInput(input)
+	if err := checkRectangular(grid); err != nil {
+		return nil, err
+	}
+	return grid, nil
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	matches, err := Search(parseInput(input), []string{"XMAS"}, SearchOptions{})
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(len(matches)), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	matches, err := MatchKernel(parseInput(input), xMasKernel)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(len(matches)), nil
+}
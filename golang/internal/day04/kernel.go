@@ -0,0 +1,226 @@
+package day04
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Kernel is a small 2D pattern to find in a grid. It is written one row per
+// line, or with rows separated by '/', where each cell is either:
+//
+//	X      a letter that must match exactly
+//	.      a wildcard that matches any letter
+//	[MS]   any one of the letters in brackets
+//
+// so the X-MAS shape is "M.S/.A./M.S".
+type Kernel struct {
+	// cells holds the letters allowed at each cell, sorted, with an empty
+	// string for a wildcard.
+	cells [][]string
+}
+
+// Point is a cell of the grid.
+type Point struct {
+	Row int
+	Col int
+}
+
+// ParseKernel reads a kernel written in the pattern language above.
+func ParseKernel(src string) (Kernel, error) {
+	lines := strings.FieldsFunc(src, func(r rune) bool {
+		return r == '/' || r == '\n'
+	})
+	if len(lines) == 0 {
+		return Kernel{}, errors.New("kernel is empty")
+	}
+
+	k := Kernel{}
+	for row, line := range lines {
+		line = strings.TrimSpace(line)
+		cells := make([]string, 0, len(line))
+		for i := 0; i < len(line); i++ {
+			switch line[i] {
+			case '.':
+				cells = append(cells, "")
+			case '[':
+				end := strings.IndexByte(line[i:], ']')
+				if end == -1 {
+					return Kernel{}, fmt.Errorf("row %d: unclosed [ at column %d", row, i)
+				}
+				alternatives := []byte(line[i+1 : i+end])
+				if len(alternatives) == 0 {
+					return Kernel{}, fmt.Errorf("row %d: empty [] at column %d", row, i)
+				}
+				slices.Sort(alternatives)
+				cells = append(cells, string(slices.Compact(alternatives)))
+				i += end
+			case ']':
+				return Kernel{}, fmt.Errorf("row %d: unopened ] at column %d", row, i)
+			default:
+				cells = append(cells, line[i:i+1])
+			}
+		}
+		if row > 0 && len(cells) != len(k.cells[0]) {
+			return Kernel{}, fmt.Errorf("row %d has %d cells, expected %d", row, len(cells), len(k.cells[0]))
+		}
+		k.cells = append(k.cells, cells)
+	}
+	if len(k.cells[0]) == 0 {
+		return Kernel{}, errors.New("kernel has no cells")
+	}
+	if !slices.ContainsFunc(k.cells, func(cells []string) bool {
+		return slices.ContainsFunc(cells, func(allowed string) bool { return allowed != "" })
+	}) {
+		return Kernel{}, errors.New("kernel is only wildcards")
+	}
+	return k, nil
+}
+
+func mustParseKernel(src string) Kernel {
+	k, err := ParseKernel(src)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// String writes the kernel back out in the pattern language, rows separated
+// by '/'.
+func (k Kernel) String() string {
+	var sb strings.Builder
+	for row, cells := range k.cells {
+		if row > 0 {
+			sb.WriteByte('/')
+		}
+		for _, allowed := range cells {
+			switch len(allowed) {
+			case 0:
+				sb.WriteByte('.')
+			case 1:
+				sb.WriteString(allowed)
+			default:
+				sb.WriteString("[" + allowed + "]")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// rotate turns the kernel a quarter turn clockwise.
+func (k Kernel) rotate() Kernel {
+	rows, cols := len(k.cells), len(k.cells[0])
+	rotated := make([][]string, cols)
+	for row := range cols {
+		rotated[row] = make([]string, rows)
+		for col := range rows {
+			rotated[row][col] = k.cells[rows-1-col][row]
+		}
+	}
+	return Kernel{cells: rotated}
+}
+
+// reflect mirrors the kernel left to right.
+func (k Kernel) reflect() Kernel {
+	reflected := make([][]string, len(k.cells))
+	for row, cells := range k.cells {
+		reflected[row] = slices.Clone(cells)
+		slices.Reverse(reflected[row])
+	}
+	return Kernel{cells: reflected}
+}
+
+type kernelVariant struct {
+	kernel    Kernel
+	transform string
+}
+
+// variants returns the kernel under every rotation and reflection, dropping
+// any that look the same as an earlier one so that a symmetric kernel doesn't
+// match the same cells more than once.
+func (k Kernel) variants() []kernelVariant {
+	variants := make([]kernelVariant, 0, 8)
+	seen := make(map[string]bool)
+	for _, start := range []kernelVariant{{kernel: k, transform: ""}, {kernel: k.reflect(), transform: "flip+"}} {
+		current := start.kernel
+		for turns := range 4 {
+			if !seen[current.String()] {
+				seen[current.String()] = true
+				variants = append(variants, kernelVariant{
+					kernel:    current,
+					transform: fmt.Sprintf("%srot%d", start.transform, turns*90),
+				})
+			}
+			current = current.rotate()
+		}
+	}
+	return variants
+}
+
+// KernelMatch is one place a kernel matched, with Row and Col the top left
+// corner of the kernel as transformed.
+type KernelMatch struct {
+	Row int
+	Col int
+	// Transform is how the kernel was turned to match, such as "rot90" or
+	// "flip+rot180".
+	Transform string
+	// Cells are the grid cells matched by non-wildcard kernel cells.
+	Cells []Point
+}
+
+// MatchKernel finds every place the kernel matches the grid, trying it under
+// all rotations and reflections. Where different transforms match exactly the
+// same cells, as "A[AB]" and its half turn do on "AA", only the first is kept.
+func MatchKernel(grid []string, k Kernel) ([]KernelMatch, error) {
+	if err := checkRectangular(grid); err != nil {
+		return nil, err
+	}
+
+	matches := make([]KernelMatch, 0)
+	matchedCells := make(map[string]bool)
+	for _, variant := range k.variants() {
+		cells := variant.kernel.cells
+		for row := 0; row+len(cells) <= len(grid); row++ {
+			for col := 0; col+len(cells[0]) <= len(grid[0]); col++ {
+				if !variant.kernel.matchesAt(grid, row, col) {
+					continue
+				}
+				match := KernelMatch{Row: row, Col: col, Transform: variant.transform}
+				for dRow, kernelRow := range cells {
+					for dCol, allowed := range kernelRow {
+						if allowed != "" {
+							match.Cells = append(match.Cells, Point{Row: row + dRow, Col: col + dCol})
+						}
+					}
+				}
+				// Cells are added in reading order, so the same cells always give
+				// the same key.
+				key := fmt.Sprint(match.Cells)
+				if matchedCells[key] {
+					continue
+				}
+				matchedCells[key] = true
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a KernelMatch, b KernelMatch) int {
+		return cmp.Or(cmp.Compare(a.Row, b.Row), cmp.Compare(a.Col, b.Col))
+	})
+	return matches, nil
+}
+
+func (k Kernel) matchesAt(grid []string, row int, col int) bool {
+	for dRow, cells := range k.cells {
+		for dCol, allowed := range cells {
+			if allowed != "" && strings.IndexByte(allowed, grid[row+dRow][col+dCol]) == -1 {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package day04
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestParseKernel(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "M.S/.A./M.S", want: "M.S/.A./M.S"},
+		{src: "M.S\n.A.\nM.S\n", want: "M.S/.A./M.S"},
+		{src: " XMAS ", want: "XMAS"},
+		{src: "[SM]A[MS]", want: "[MS]A[MS]"},
+		{src: "[MMS].", want: "[MS]."},
+		{src: "[A]", want: "A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			k, err := ParseKernel(tt.src)
+			if err != nil {
+				t.Fatalf("ParseKernel() error = %v", err)
+			}
+			if got := k.String(); got != tt.want {
+				t.Errorf("ParseKernel().String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKernelErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "empty", src: ""},
+		{name: "only separators", src: "//\n"},
+		{name: "blank row", src: "  /AB"},
+		{name: "ragged", src: "M.S/.A/M.S"},
+		{name: "ragged brackets", src: "[MS]A/MAS"},
+		{name: "unclosed bracket", src: "M[AS"},
+		{name: "empty brackets", src: "M[]S"},
+		{name: "unopened bracket", src: "MA]S"},
+		{name: "only wildcards", src: "../.."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if k, err := ParseKernel(tt.src); err == nil {
+				t.Errorf("ParseKernel(%q) = %v, want an error", tt.src, k)
+			}
+		})
+	}
+}
+
+func TestKernelTransforms(t *testing.T) {
+	k := mustParseKernel("AB/C.")
+	if got, want := k.rotate().String(), "CA/.B"; got != want {
+		t.Errorf("rotate() = %q, want %q", got, want)
+	}
+	if got, want := k.reflect().String(), "BA/.C"; got != want {
+		t.Errorf("reflect() = %q, want %q", got, want)
+	}
+	if got := k.rotate().rotate().rotate().rotate().String(); got != k.String() {
+		t.Errorf("four rotations = %q, want %q back", got, k.String())
+	}
+	wide := mustParseKernel("XMAS")
+	if got, want := wide.rotate().String(), "X/M/A/S"; got != want {
+		t.Errorf("rotate() = %q, want %q", got, want)
+	}
+}
+
+func TestKernelVariants(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{
+			src:  "AB/C.",
+			want: []string{"AB/C.", "CA/.B", ".C/BA", "B./AC", "BA/.C", ".B/CA", "C./AB", "AC/B."},
+		},
+		// Reflecting a word reads it backwards, which is already its half turn.
+		{src: "XMAS", want: []string{"XMAS", "X/M/A/S", "SAMX", "S/A/M/X"}},
+		{src: "M.S/.A./M.S", want: []string{"M.S/.A./M.S", "M.M/.A./S.S", "S.M/.A./S.M", "S.S/.A./M.M"}},
+		{src: "AB/BA", want: []string{"AB/BA", "BA/AB"}},
+		{src: "[AB][AB]/[AB][AB]", want: []string{"[AB][AB]/[AB][AB]"}},
+		{src: "A", want: []string{"A"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			variants := mustParseKernel(tt.src).variants()
+			got := make([]string, len(variants))
+			for i, v := range variants {
+				got[i] = v.kernel.String()
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("variants() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchKernel(t *testing.T) {
+	tests := []struct {
+		name   string
+		grid   []string
+		kernel string
+		want   []KernelMatch
+	}{
+		{
+			name:   "one x-mas",
+			grid:   []string{"MXS", "XAX", "MXS"},
+			kernel: "M.S/.A./M.S",
+			want: []KernelMatch{
+				{Row: 0, Col: 0, Transform: "rot0", Cells: []Point{{0, 0}, {0, 2}, {1, 1}, {2, 0}, {2, 2}}},
+			},
+		},
+		{
+			name:   "turned x-mas",
+			grid:   []string{"SXS", "XAX", "MXM"},
+			kernel: "M.S/.A./M.S",
+			want: []KernelMatch{
+				{Row: 0, Col: 0, Transform: "rot270", Cells: []Point{{0, 0}, {0, 2}, {1, 1}, {2, 0}, {2, 2}}},
+			},
+		},
+		{
+			name:   "same cells under a half turn",
+			grid:   []string{"AA"},
+			kernel: "A[AB]",
+			want: []KernelMatch{
+				{Row: 0, Col: 0, Transform: "rot0", Cells: []Point{{0, 0}, {0, 1}}},
+			},
+		},
+		{
+			name:   "wildcards match anything",
+			grid:   []string{"AZ", "QB"},
+			kernel: "A./.B",
+			want: []KernelMatch{
+				{Row: 0, Col: 0, Transform: "rot0", Cells: []Point{{0, 0}, {1, 1}}},
+			},
+		},
+		{
+			name:   "kernel bigger than grid",
+			grid:   []string{"XM"},
+			kernel: "XMAS",
+			want:   []KernelMatch{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchKernel(tt.grid, mustParseKernel(tt.kernel))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(got, tt.want, func(a KernelMatch, b KernelMatch) bool {
+				return a.Row == b.Row && a.Col == b.Col && a.Transform == b.Transform && slices.Equal(a.Cells, b.Cells)
+			}) {
+				t.Errorf("MatchKernel() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := MatchKernel([]string{"MAS", "MA"}, xMasKernel); err == nil {
+		t.Error("MatchKernel() on a ragged grid error = nil, want an error")
+	}
+}
+
+func TestMatchKernelExample(t *testing.T) {
+	input, err := os.ReadFile("testdata/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid, err := ParseGrid(string(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err := MatchKernel(grid, xMasKernel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 9 {
+		t.Errorf("MatchKernel() found %d X-MAS, want 9", len(matches))
+	}
+}
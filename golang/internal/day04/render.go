@@ -0,0 +1,191 @@
+package day04
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// Cells returns the cells a match covers, in reading order of the word. The
+// grid size is needed to wrap matches found on a torus.
+func (m Match) Cells(rows int, cols int) []Point {
+	cells := make([]Point, len(m.Word))
+	for i := range m.Word {
+		cells[i] = Point{
+			Row: ((m.Row+i*m.Direction.DRow)%rows + rows) % rows,
+			Col: ((m.Col+i*m.Direction.DCol)%cols + cols) % cols,
+		}
+	}
+	return cells
+}
+
+// transforms are the kernel transforms in the order variants tries them.
+var transforms = []string{
+	"rot0", "rot90", "rot180", "rot270",
+	"flip+rot0", "flip+rot90", "flip+rot180", "flip+rot270",
+}
+
+// ANSI and HTML colours, picked by the position of a match's key in
+// CellSet.Keys, with the mixed colour for a cell covered by matches with
+// different keys.
+var ansiColors = []string{"31", "32", "33", "34", "35", "36", "91", "92"}
+
+const ansiMixedColor = "1;97"
+
+var htmlColors = []string{
+	"#d62728", "#2ca02c", "#bcbd22", "#1f77b4",
+	"#9467bd", "#17becf", "#ff7f0e", "#8c564b",
+}
+
+const htmlMixedColor = "#000000"
+
+// cellMark is one match covering a cell.
+type cellMark struct {
+	key   string
+	label string
+}
+
+// CellSet is the cells covered by a set of matches, which is what the
+// renderers draw. Each match has a key that picks its colour, such as the
+// direction of a word or the transform of a kernel, and a label that says
+// which match it is.
+type CellSet struct {
+	// Keys lists every key a match may have, in the order they are coloured
+	// and listed in the HTML legend.
+	Keys    []string
+	marks   map[Point][]cellMark
+	matches int
+}
+
+// NewCellSet returns an empty set whose matches are keyed by keys.
+func NewCellSet(keys []string) CellSet {
+	return CellSet{Keys: keys, marks: make(map[Point][]cellMark)}
+}
+
+// Add marks cells as covered by one match.
+func (s *CellSet) Add(key string, label string, cells []Point) {
+	for _, p := range cells {
+		s.marks[p] = append(s.marks[p], cellMark{key: key, label: label})
+	}
+	s.matches += 1
+}
+
+// WordCells returns the cells covered by word search matches, keyed by
+// direction.
+func WordCells(grid []string, matches []Match) CellSet {
+	keys := make([]string, len(Directions))
+	for i, d := range Directions {
+		keys[i] = d.String()
+	}
+	s := NewCellSet(keys)
+	for _, m := range matches {
+		s.Add(m.Direction.String(), fmt.Sprintf("%s at %d,%d %s", m.Word, m.Row, m.Col, m.Direction), m.Cells(len(grid), len(grid[0])))
+	}
+	return s
+}
+
+// KernelCells returns the cells covered by matches of k, keyed by transform.
+// Only the kernel's non-wildcard cells are covered.
+func KernelCells(k Kernel, matches []KernelMatch) CellSet {
+	s := NewCellSet(transforms)
+	for _, m := range matches {
+		s.Add(m.Transform, fmt.Sprintf("%s at %d,%d %s", k, m.Row, m.Col, m.Transform), m.Cells)
+	}
+	return s
+}
+
+// colorIndex returns the position of the key every match covering p has, or
+// false if they don't agree.
+func (s CellSet) colorIndex(p Point) (int, bool) {
+	marks := s.marks[p]
+	for _, mark := range marks[1:] {
+		if mark.key != marks[0].key {
+			return 0, false
+		}
+	}
+	i := slices.Index(s.Keys, marks[0].key)
+	return i, i != -1
+}
+
+// RenderOptions configures Render.
+type RenderOptions struct {
+	// Color colours each matched letter by its match's key with ANSI escapes.
+	// Letters covered by matches with different keys are bold white.
+	Color bool
+}
+
+// Render draws the grid keeping only the letters that are part of a match,
+// with every other letter replaced by '.', as in the puzzle's illustrations.
+func Render(grid []string, cells CellSet, opts RenderOptions) string {
+	var sb strings.Builder
+	for row, line := range grid {
+		for col := 0; col < len(line); col++ {
+			p := Point{Row: row, Col: col}
+			if len(cells.marks[p]) == 0 {
+				sb.WriteByte('.')
+				continue
+			}
+			if !opts.Color {
+				sb.WriteByte(line[col])
+				continue
+			}
+			color := ansiMixedColor
+			if i, ok := cells.colorIndex(p); ok {
+				color = ansiColors[i%len(ansiColors)]
+			}
+			fmt.Fprintf(&sb, "\x1b[%sm%c\x1b[0m", color, line[col])
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// RenderHTML draws the grid as Render does, as a standalone HTML page with the
+// matched letters coloured by key. Hovering over a letter lists every match
+// it is part of, which makes a letter counted twice easy to spot.
+func RenderHTML(grid []string, cells CellSet) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Word search</title>\n")
+	sb.WriteString("<style>pre { font-size: 16px; line-height: 1.2; } span { font-weight: bold; }</style>\n")
+	sb.WriteString("</head>\n<body>\n")
+
+	sb.WriteString("<p>")
+	for i, key := range cells.Keys {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		fmt.Fprintf(&sb, "<span style=\"color: %s\">%s</span>", htmlColors[i%len(htmlColors)], html.EscapeString(key))
+	}
+	fmt.Fprintf(&sb, " <span style=\"color: %s\">mixed</span></p>\n", htmlMixedColor)
+	fmt.Fprintf(&sb, "<p>%d match(es)</p>\n<pre>\n", cells.matches)
+
+	for row, line := range grid {
+		for col := 0; col < len(line); col++ {
+			p := Point{Row: row, Col: col}
+			letter := html.EscapeString(line[col : col+1])
+			if len(cells.marks[p]) == 0 {
+				sb.WriteByte('.')
+				continue
+			}
+			color := htmlMixedColor
+			if i, ok := cells.colorIndex(p); ok {
+				color = htmlColors[i%len(htmlColors)]
+			}
+			titles := make([]string, len(cells.marks[p]))
+			for i, mark := range cells.marks[p] {
+				titles[i] = mark.label
+			}
+			fmt.Fprintf(
+				&sb,
+				"<span style=\"color: %s\" title=\"%s\">%s</span>",
+				color,
+				html.EscapeString(strings.Join(titles, "\n")),
+				letter,
+			)
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("</pre>\n</body>\n</html>\n")
+	return sb.String()
+}
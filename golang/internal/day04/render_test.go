@@ -0,0 +1,105 @@
+package day04
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestRenderGolden(t *testing.T) {
+	input, err := os.ReadFile("testdata/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid, err := ParseGrid(string(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	words, err := Search(grid, []string{"XMAS"}, SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kernelMatches, err := MatchKernel(grid, xMasKernel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wordCells := WordCells(grid, words)
+	kernelCells := KernelCells(xMasKernel, kernelMatches)
+
+	tests := []struct {
+		golden string
+		render func() string
+	}{
+		{golden: "words.txt", render: func() string { return Render(grid, wordCells, RenderOptions{}) }},
+		{golden: "words.ansi", render: func() string { return Render(grid, wordCells, RenderOptions{Color: true}) }},
+		{golden: "words.html", render: func() string { return RenderHTML(grid, wordCells) }},
+		{golden: "kernel.txt", render: func() string { return Render(grid, kernelCells, RenderOptions{}) }},
+		{golden: "kernel.ansi", render: func() string { return Render(grid, kernelCells, RenderOptions{Color: true}) }},
+		{golden: "kernel.html", render: func() string { return RenderHTML(grid, kernelCells) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			path := filepath.Join("testdata", "render", tt.golden)
+			got := tt.render()
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("render doesn't match %s, rerun with -update if the change is intended:\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestRenderHTMLEscapes(t *testing.T) {
+	grid := []string{"<&>", "\"'x"}
+	words, err := Search(grid, []string{"<&>", "\"'"}, SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := RenderHTML(grid, WordCells(grid, words))
+	for _, want := range []string{
+		`title="&lt;&amp;&gt; at 0,0 E">&lt;</span>`,
+		`title="&lt;&amp;&gt; at 0,0 E">&amp;</span>`,
+		`title="&#34;&#39; at 1,0 E">&#34;</span>`,
+		`title="&#34;&#39; at 1,0 E">&#39;</span>.`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderHTML() = %s, want it to contain %s", got, want)
+		}
+	}
+	if strings.Contains(got, "<&>") {
+		t.Errorf("RenderHTML() = %s, has the grid's letters unescaped", got)
+	}
+}
+
+func TestCellSetMixed(t *testing.T) {
+	grid := []string{"AB", "C."}
+	s := NewCellSet([]string{"one", "two"})
+	s.Add("one", "first", []Point{{0, 0}, {0, 1}})
+	s.Add("two", "second", []Point{{0, 0}, {1, 0}})
+	s.Add("one", "third", []Point{{0, 1}})
+
+	want := "\x1b[1;97mA\x1b[0m\x1b[31mB\x1b[0m\n\x1b[32mC\x1b[0m.\n"
+	if got := Render(grid, s, RenderOptions{Color: true}); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+	html := RenderHTML(grid, s)
+	if !strings.Contains(html, `<span style="color: #000000" title="first`+"\n"+`second">A</span>`) {
+		t.Errorf("RenderHTML() = %s, want A in the mixed colour titled with both matches", html)
+	}
+	if !strings.Contains(html, "<p>3 match(es)</p>") {
+		t.Errorf("RenderHTML() = %s, want 3 matches counted", html)
+	}
+}
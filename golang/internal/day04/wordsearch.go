@@ -0,0 +1,265 @@
+package day04
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Direction is the step from one letter of a word to the next.
+type Direction struct {
+	DRow int
+	DCol int
+}
+
+var (
+	East      = Direction{DRow: 0, DCol: 1}
+	SouthEast = Direction{DRow: 1, DCol: 1}
+	South     = Direction{DRow: 1, DCol: 0}
+	SouthWest = Direction{DRow: 1, DCol: -1}
+	West      = Direction{DRow: 0, DCol: -1}
+	NorthWest = Direction{DRow: -1, DCol: -1}
+	North     = Direction{DRow: -1, DCol: 0}
+	NorthEast = Direction{DRow: -1, DCol: 1}
+)
+
+// Directions lists all eight directions clockwise from East.
+var Directions = []Direction{East, SouthEast, South, SouthWest, West, NorthWest, North, NorthEast}
+
+var directionNames = map[Direction]string{
+	East:      "E",
+	SouthEast: "SE",
+	South:     "S",
+	SouthWest: "SW",
+	West:      "W",
+	NorthWest: "NW",
+	North:     "N",
+	NorthEast: "NE",
+}
+
+func (d Direction) String() string {
+	if name, ok := directionNames[d]; ok {
+		return name
+	}
+	return fmt.Sprintf("(%d,%d)", d.DRow, d.DCol)
+}
+
+// Match is one place a word was found, reading from Row and Col in Direction.
+type Match struct {
+	Word      string
+	Row       int
+	Col       int
+	Direction Direction
+}
+
+// SearchOptions configures Search.
+type SearchOptions struct {
+	// Toroidal lets words run off one edge of the grid and back in at the
+	// opposite one.
+	Toroidal bool
+}
+
+// Search finds every occurrence of words in the grid, reading in all eight
+// directions. All the words are looked for at once by running an Aho-Corasick
+// automaton along every line of the grid, so each direction is a single pass.
+// A single letter word is only reported once per cell, reading East.
+func Search(grid []string, words []string, opts SearchOptions) ([]Match, error) {
+	if err := checkRectangular(grid); err != nil {
+		return nil, err
+	}
+	ac, err := newAhoCorasick(words)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0)
+	for _, d := range Directions {
+		for _, line := range gridLines(grid, d, opts.Toroidal, ac.longest) {
+			ac.scan(line.letters, func(word string, start int) {
+				if start >= line.length || (len(word) == 1 && d != East) {
+					return
+				}
+				cell := line.cells[start]
+				matches = append(matches, Match{Word: word, Row: cell.row, Col: cell.col, Direction: d})
+			})
+		}
+	}
+
+	slices.SortFunc(matches, func(a Match, b Match) int {
+		return cmp.Or(
+			cmp.Compare(a.Row, b.Row),
+			cmp.Compare(a.Col, b.Col),
+			cmp.Compare(slices.Index(Directions, a.Direction), slices.Index(Directions, b.Direction)),
+			cmp.Compare(a.Word, b.Word),
+		)
+	})
+	return matches, nil
+}
+
+func checkRectangular(grid []string) error {
+	if len(grid) == 0 {
+		return errors.New("grid is empty")
+	}
+	for row, line := range grid {
+		if len(line) != len(grid[0]) {
+			return fmt.Errorf("row %d is %d wide, expected %d", row, len(line), len(grid[0]))
+		}
+	}
+	return nil
+}
+
+type cell struct {
+	row int
+	col int
+}
+
+// gridLine is the letters read along one line of the grid. On a torus the line
+// is a loop, so the first overlap cells are repeated at the end to find words
+// that wrap around. Only matches starting within the first length cells are
+// new.
+type gridLine struct {
+	cells   []cell
+	letters []byte
+	length  int
+}
+
+// gridLines splits the grid into lines running in direction d, covering every
+// cell once.
+func gridLines(grid []string, d Direction, toroidal bool, longestWord int) []gridLine {
+	rows, cols := len(grid), len(grid[0])
+	inBounds := func(c cell) bool {
+		return c.row >= 0 && c.row < rows && c.col >= 0 && c.col < cols
+	}
+	step := func(c cell) cell {
+		next := cell{row: c.row + d.DRow, col: c.col + d.DCol}
+		if toroidal {
+			next.row = (next.row + rows) % rows
+			next.col = (next.col + cols) % cols
+		}
+		return next
+	}
+
+	lines := make([]gridLine, 0)
+	visited := make([]bool, rows*cols)
+	for row := range rows {
+		for col := range cols {
+			start := cell{row: row, col: col}
+			if visited[row*cols+col] {
+				continue
+			}
+			// Off the torus, lines start at the edge they run away from.
+			if !toroidal && inBounds(cell{row: row - d.DRow, col: col - d.DCol}) {
+				continue
+			}
+
+			line := gridLine{}
+			for c := start; inBounds(c) && !visited[c.row*cols+c.col]; c = step(c) {
+				visited[c.row*cols+c.col] = true
+				line.cells = append(line.cells, c)
+			}
+			line.length = len(line.cells)
+			if toroidal {
+				for i := 0; i < longestWord-1; i++ {
+					line.cells = append(line.cells, line.cells[i%line.length])
+				}
+			}
+			line.letters = make([]byte, len(line.cells))
+			for i, c := range line.cells {
+				line.letters[i] = grid[c.row][c.col]
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// ahoCorasick matches many words in one pass over some text.
+type ahoCorasick struct {
+	next []map[byte]int
+	fail []int
+	// outputs holds the words ending at each node, including those reached by
+	// following fail links.
+	outputs [][]string
+	longest int
+}
+
+func newAhoCorasick(words []string) (*ahoCorasick, error) {
+	if len(words) == 0 {
+		return nil, errors.New("no words to search for")
+	}
+	ac := &ahoCorasick{
+		next:    []map[byte]int{{}},
+		fail:    []int{0},
+		outputs: [][]string{nil},
+	}
+
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if word == "" {
+			return nil, errors.New("can't search for an empty word")
+		}
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		ac.longest = max(ac.longest, len(word))
+
+		node := 0
+		for i := 0; i < len(word); i++ {
+			child, ok := ac.next[node][word[i]]
+			if !ok {
+				child = len(ac.next)
+				ac.next = append(ac.next, map[byte]int{})
+				ac.fail = append(ac.fail, 0)
+				ac.outputs = append(ac.outputs, nil)
+				ac.next[node][word[i]] = child
+			}
+			node = child
+		}
+		ac.outputs[node] = append(ac.outputs[node], word)
+	}
+
+	// Fail links point at the longest proper suffix that is also in the trie,
+	// worked out breadth first so that shorter suffixes are ready first.
+	queue := make([]int, 0)
+	for _, child := range ac.next[0] {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for b, child := range ac.next[node] {
+			fail := ac.fail[node]
+			for fail != 0 {
+				if _, ok := ac.next[fail][b]; ok {
+					break
+				}
+				fail = ac.fail[fail]
+			}
+			if target, ok := ac.next[fail][b]; ok && target != child {
+				ac.fail[child] = target
+			}
+			ac.outputs[child] = append(ac.outputs[child], ac.outputs[ac.fail[child]]...)
+			queue = append(queue, child)
+		}
+	}
+	return ac, nil
+}
+
+// scan calls found with each word in text and the index it starts at.
+func (ac *ahoCorasick) scan(text []byte, found func(word string, start int)) {
+	node := 0
+	for i, b := range text {
+		for node != 0 {
+			if _, ok := ac.next[node][b]; ok {
+				break
+			}
+			node = ac.fail[node]
+		}
+		node = ac.next[node][b]
+		for _, word := range ac.outputs[node] {
+			found(word, i-len(word)+1)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package day04
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	grid := []string{
+		"XMAS",
+		"MASX",
+		"SAMX",
+	}
+	tests := []struct {
+		name  string
+		words []string
+		opts  SearchOptions
+		want  []Match
+	}{
+		{
+			name:  "overlapping words",
+			words: []string{"XMAS", "MAS", "AS"},
+			want: []Match{
+				{Word: "XMAS", Row: 0, Col: 0, Direction: East},
+				{Word: "MAS", Row: 0, Col: 1, Direction: East},
+				{Word: "AS", Row: 0, Col: 2, Direction: East},
+				{Word: "AS", Row: 0, Col: 2, Direction: South},
+				{Word: "MAS", Row: 1, Col: 0, Direction: East},
+				{Word: "AS", Row: 1, Col: 1, Direction: East},
+				{Word: "AS", Row: 1, Col: 1, Direction: SouthWest},
+				{Word: "AS", Row: 2, Col: 1, Direction: West},
+				{Word: "AS", Row: 2, Col: 1, Direction: NorthEast},
+				{Word: "MAS", Row: 2, Col: 2, Direction: West},
+				{Word: "XMAS", Row: 2, Col: 3, Direction: West},
+			},
+		},
+		{
+			name:  "wrapping round a torus",
+			words: []string{"SXMA"},
+			opts:  SearchOptions{Toroidal: true},
+			want: []Match{
+				{Word: "SXMA", Row: 0, Col: 3, Direction: East},
+				{Word: "SXMA", Row: 1, Col: 2, Direction: East},
+				{Word: "SXMA", Row: 2, Col: 0, Direction: West},
+			},
+		},
+		{
+			name:  "single letters only read East",
+			words: []string{"X", "X"},
+			want: []Match{
+				{Word: "X", Row: 0, Col: 0, Direction: East},
+				{Word: "X", Row: 1, Col: 3, Direction: East},
+				{Word: "X", Row: 2, Col: 3, Direction: East},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Search(grid, tt.words, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		grid  []string
+		words []string
+	}{
+		{name: "no words", grid: []string{"XMAS"}},
+		{name: "empty word", grid: []string{"XMAS"}, words: []string{""}},
+		{name: "empty grid", words: []string{"XMAS"}},
+		{name: "ragged grid", grid: []string{"XMAS", "XM"}, words: []string{"XMAS"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Search(tt.grid, tt.words, SearchOptions{}); err == nil {
+				t.Error("Search() error = nil, want an error")
+			}
+		})
+	}
+}
+
+// sameMatches compares matches ignoring their order.
+func sameMatches(a []Match, b []Match) bool {
+	key := func(m Match) string { return fmt.Sprint(m) }
+	aKeys := make([]string, len(a))
+	for i, m := range a {
+		aKeys[i] = key(m)
+	}
+	bKeys := make([]string, len(b))
+	for i, m := range b {
+		bKeys[i] = key(m)
+	}
+	slices.Sort(aKeys)
+	slices.Sort(bKeys)
+	return slices.Equal(aKeys, bKeys)
+}
+
+// searchBruteForce reads every word from every cell in every direction.
+func searchBruteForce(grid []string, words []string, toroidal bool) []Match {
+	rows, cols := len(grid), len(grid[0])
+	slices.Sort(words)
+	words = slices.Compact(words)
+	matches := make([]Match, 0)
+	for row := range rows {
+		for col := range cols {
+			for _, d := range Directions {
+				for _, word := range words {
+					if len(word) == 1 && d != East {
+						continue
+					}
+					found := true
+					for i := range len(word) {
+						r, c := row+i*d.DRow, col+i*d.DCol
+						if toroidal {
+							r, c = ((r%rows)+rows)%rows, ((c%cols)+cols)%cols
+						}
+						if r < 0 || r >= rows || c < 0 || c >= cols || grid[r][c] != word[i] {
+							found = false
+							break
+						}
+					}
+					if found {
+						matches = append(matches, Match{Word: word, Row: row, Col: col, Direction: d})
+					}
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// TestSearchBruteForce checks the Aho-Corasick search against reading every
+// word from every cell, on small random grids with few letters so that words
+// overlap and repeat a lot.
+func TestSearchBruteForce(t *testing.T) {
+	const letters = "XMAS"
+	rng := rand.New(rand.NewSource(1))
+	randomString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = letters[rng.Intn(len(letters))]
+		}
+		return string(b)
+	}
+	for i := range 500 {
+		rows, cols := 1+rng.Intn(6), 1+rng.Intn(6)
+		grid := make([]string, rows)
+		for row := range grid {
+			grid[row] = randomString(cols)
+		}
+		words := make([]string, 1+rng.Intn(4))
+		for j := range words {
+			words[j] = randomString(1 + rng.Intn(7))
+		}
+		toroidal := i%2 == 1
+
+		got, err := Search(grid, words, SearchOptions{Toroidal: toroidal})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := searchBruteForce(grid, slices.Clone(words), toroidal)
+		if !sameMatches(got, want) {
+			t.Fatalf("Search(%q, %q, toroidal %v) = %v, want %v", grid, words, toroidal, got, want)
+		}
+	}
+}
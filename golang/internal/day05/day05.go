@@ -0,0 +1,157 @@
+// Package day05 solves day 5, Print Queue.
+package day05
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ordering struct {
+	before int
+	after  int
+}
+
+type pageUpdate struct {
+	pageNums []int
+}
+
+// sort puts the pages in the order the rules give, breaking ties by where the
+// pages were in the update, and returns that order. It fails if the rules
+// allow no order, or allow orders with different middle pages, as the answer
+// would then depend on the tie break.
+func (pu *pageUpdate) sort(rules *ruleGraph) (pageOrder, error) {
+	order, err := rules.order(pu.pageNums)
+	if err != nil {
+		return pageOrder{}, err
+	}
+	if !order.unique {
+		if middles := rules.middlePages(pu.pageNums); len(middles) > 1 {
+			return pageOrder{}, &AmbiguousMiddleError{Middles: middles}
+		}
+	}
+	pu.pageNums = order.pages
+	return order, nil
+}
+
+// isSorted checks every pair of pages, not just neighbours, as the rules
+// needn't be transitive.
+func (pu *pageUpdate) isSorted(rules *ruleGraph) bool {
+	for i, earlier := range pu.pageNums {
+		for _, later := range pu.pageNums[i+1:] {
+			if rules.mustPrecede(later, earlier) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (pu *pageUpdate) middlePage() int {
+	pageCount := len(pu.pageNums)
+	index := pageCount / 2
+	return pu.pageNums[index]
+}
+
+func handleLineFirstSection(line string) (int, int, error) {
+	numStrings := strings.Split(line, "|")
+	if len(numStrings) != 2 {
+		return 0, 0, fmt.Errorf("expected an ordering rule like 47|53, got %q", line)
+	}
+
+	lhs, err := strconv.Atoi(numStrings[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	rhs, err := strconv.Atoi(numStrings[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lhs, rhs, nil
+}
+
+func handleLineSecondSection(line string) ([]int, error) {
+	pageNumStrings := strings.Split(line, ",")
+
+	pageNums := make([]int, len(pageNumStrings))
+
+	for i := range pageNumStrings {
+		num, err := strconv.Atoi(pageNumStrings[i])
+		if err != nil {
+			return nil, err
+		}
+		pageNums[i] = num
+	}
+
+	return pageNums, nil
+}
+
+func parseInput(input string) ([]ordering, []pageUpdate, error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	section := 0
+
+	orderings := make([]ordering, 0)
+	pageNumUpdates := make([]pageUpdate, 0)
+
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			section += 1
+			continue
+		}
+		if section == 0 {
+			lhs, rhs, err := handleLineFirstSection(scanner.Text())
+			if err != nil {
+				return nil, nil, err
+			}
+			orderings = append(orderings, ordering{lhs, rhs})
+		} else if section == 1 {
+			nums, err := handleLineSecondSection(scanner.Text())
+			if err != nil {
+				return nil, nil, err
+			}
+
+			pageNumUpdates = append(pageNumUpdates, pageUpdate{pageNums: nums})
+		}
+	}
+
+	return orderings, pageNumUpdates, nil
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	orderings, pageNumUpdates, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	rules := newRuleGraph(orderings)
+
+	partOneSum := 0
+	for _, pageNumUpdate := range pageNumUpdates {
+		if pageNumUpdate.isSorted(rules) {
+			partOneSum += pageNumUpdate.middlePage()
+		}
+	}
+	return strconv.Itoa(partOneSum), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	orderings, pageNumUpdates, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	rules := newRuleGraph(orderings)
+
+	partTwoSum := 0
+	for i, pageNumUpdate := range pageNumUpdates {
+		if !pageNumUpdate.isSorted(rules) {
+			if _, err := pageNumUpdate.sort(rules); err != nil {
+				return "", fmt.Errorf("update %d: %w", i+1, err)
+			}
+			partTwoSum += pageNumUpdate.middlePage()
+		}
+	}
+	return strconv.Itoa(partTwoSum), nil
+}
@@ -0,0 +1,212 @@
+package day05
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+// MaxCountPages is the most pages CountOrderings and MiddlePages take on. The
+// DP keeps a count for every subset of pages, so the memory it needs doubles
+// with every page, to 128MiB at this size.
+const MaxCountPages = 24
+
+// ErrTooManyPages is returned for updates longer than MaxCountPages.
+var ErrTooManyPages = errors.New("too many pages to count orders for")
+
+// Rules are the ordering rules from a puzzle input.
+type Rules struct {
+	graph *ruleGraph
+}
+
+// Parse reads the rules and updates from a puzzle input.
+func Parse(input string) (Rules, [][]int, error) {
+	orderings, updates, err := parseInput(input)
+	if err != nil {
+		return Rules{}, nil, err
+	}
+	pages := make([][]int, len(updates))
+	for i, update := range updates {
+		pages[i] = update.pageNums
+	}
+	return Rules{graph: newRuleGraph(orderings)}, pages, nil
+}
+
+// predecessorMasks returns, for each page, a bitmask of the pages in the
+// update that must come before it.
+func (r Rules) predecessorMasks(pages []int) ([]uint32, error) {
+	if len(pages) > MaxCountPages {
+		return nil, fmt.Errorf("%w: update has %d pages, limit is %d", ErrTooManyPages, len(pages), MaxCountPages)
+	}
+	predecessors := make([]uint32, len(pages))
+	for i, page := range pages {
+		for j, other := range pages {
+			if r.graph.mustPrecede(other, page) {
+				predecessors[i] |= 1 << j
+			}
+		}
+	}
+	return predecessors, nil
+}
+
+// tooManyOrders stands in for a count too large for an int.
+const tooManyOrders = -1
+
+// prefixCounts returns how many ways each set of pages, as a bitmask, can be
+// ordered as the start of an update, or tooManyOrders if that overflows. Sets
+// that can't start an update, as a page in them needs one outside them first,
+// count zero.
+func prefixCounts(predecessors []uint32) []int {
+	counts := make([]int, 1<<len(predecessors))
+	counts[0] = 1
+	for mask := range counts {
+		if counts[mask] == 0 {
+			continue
+		}
+		for i, needs := range predecessors {
+			bit := uint32(1) << i
+			if uint32(mask)&bit != 0 || needs&^uint32(mask) != 0 {
+				continue
+			}
+			next := uint32(mask) | bit
+			if counts[mask] == tooManyOrders || counts[next] == tooManyOrders {
+				counts[next] = tooManyOrders
+				continue
+			}
+			sum, ok := checked.Add(counts[next], counts[mask])
+			if !ok {
+				sum = tooManyOrders
+			}
+			counts[next] = sum
+		}
+	}
+	return counts
+}
+
+// CountOrderings returns how many orders of pages satisfy the rules, which is
+// zero if the rules between them form a cycle. It uses a DP over subsets of
+// pages, so updates are limited to MaxCountPages, and returns an error
+// wrapping checked.ErrOverflow if there are too many orders for an int.
+func (r Rules) CountOrderings(pages []int) (int, error) {
+	predecessors, err := r.predecessorMasks(pages)
+	if err != nil {
+		return 0, err
+	}
+	counts := prefixCounts(predecessors)
+	count := counts[len(counts)-1]
+	if count == tooManyOrders {
+		return 0, fmt.Errorf("%w: orders of %d pages", checked.ErrOverflow, len(pages))
+	}
+	return count, nil
+}
+
+// MiddlePages returns every page that is in the middle of at least one valid
+// order of pages, in the order they appear in the update. The puzzle's answer
+// for the update is only certain when there is exactly one.
+func (r Rules) MiddlePages(pages []int) ([]int, error) {
+	predecessors, err := r.predecessorMasks(pages)
+	if err != nil {
+		return nil, err
+	}
+	counts := prefixCounts(predecessors)
+	if counts[len(counts)-1] == 0 {
+		return nil, nil
+	}
+
+	// A page can go in the middle if some valid start of the update fills
+	// every place before the middle and leaves that page free to go next.
+	// The rest can always be finished, as the rules have no cycle.
+	middle := len(pages) / 2
+	possible := make([]bool, len(pages))
+	for mask, count := range counts {
+		if count == 0 || bitCount(uint32(mask)) != middle {
+			continue
+		}
+		for i, needs := range predecessors {
+			if uint32(mask)&(1<<i) == 0 && needs&^uint32(mask) == 0 {
+				possible[i] = true
+			}
+		}
+	}
+
+	middles := make([]int, 0)
+	for i, page := range pages {
+		if possible[i] {
+			middles = append(middles, page)
+		}
+	}
+	return middles, nil
+}
+
+func bitCount(mask uint32) int {
+	count := 0
+	for ; mask != 0; mask &= mask - 1 {
+		count += 1
+	}
+	return count
+}
+
+// Orderings lazily yields every order of pages that satisfies the rules,
+// trying pages in the order they appear in the update at each step. It has no
+// size limit, as nothing is worked out until asked for, but the number of
+// orders can grow factorially. Each yielded slice is only valid until the
+// next is asked for.
+//
+// Nothing is yielded for an update that repeats a page or whose rules form a
+// cycle. Both are checked up front: without a cycle, any page that is free to
+// go next leads on to at least one full order, so the search never has to
+// back out of a dead end.
+func (r Rules) Orderings(pages []int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		if _, err := r.graph.order(pages); err != nil {
+			return
+		}
+
+		waitingOn := make([]int, len(pages))
+		for i, page := range pages {
+			for _, other := range pages {
+				if r.graph.mustPrecede(other, page) {
+					waitingOn[i] += 1
+				}
+			}
+		}
+
+		placed := make([]bool, len(pages))
+		order := make([]int, 0, len(pages))
+		var extend func() bool
+		extend = func() bool {
+			if len(order) == len(pages) {
+				return yield(order)
+			}
+			for i, page := range pages {
+				if placed[i] || waitingOn[i] > 0 {
+					continue
+				}
+				placed[i] = true
+				order = append(order, page)
+				r.release(pages, page, waitingOn, -1)
+				keepGoing := extend()
+				r.release(pages, page, waitingOn, 1)
+				order = order[:len(order)-1]
+				placed[i] = false
+				if !keepGoing {
+					return false
+				}
+			}
+			return true
+		}
+		extend()
+	}
+}
+
+// release adjusts the count of pages each page is waiting on by delta for
+// every page that must follow page.
+func (r Rules) release(pages []int, page int, waitingOn []int, delta int) {
+	for i, other := range pages {
+		if r.graph.mustPrecede(page, other) {
+			waitingOn[i] += delta
+		}
+	}
+}
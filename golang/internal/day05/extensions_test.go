@@ -0,0 +1,161 @@
+package day05
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+// parseRules builds rules from "a|b" pairs.
+func parseRules(t *testing.T, pairs ...string) Rules {
+	t.Helper()
+	rules, _, err := Parse(strings.Join(pairs, "\n") + "\n\n1\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rules
+}
+
+// validOrders tries every permutation of pages against the rules.
+func validOrders(rules Rules, pages []int) [][]int {
+	valid := make([][]int, 0)
+	var permute func(k int)
+	permute = func(k int) {
+		if k == len(pages) {
+			if len(rules.graph.violations(pages)) == 0 {
+				valid = append(valid, slices.Clone(pages))
+			}
+			return
+		}
+		for i := k; i < len(pages); i++ {
+			pages[k], pages[i] = pages[i], pages[k]
+			permute(k + 1)
+			pages[k], pages[i] = pages[i], pages[k]
+		}
+	}
+	permute(0)
+	return valid
+}
+
+// TestOrderingsBruteForce checks counting, middle pages and enumeration
+// against trying every permutation, on small random rule sets that are
+// sometimes cyclic.
+func TestOrderingsBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for range 300 {
+		n := 1 + rng.Intn(6)
+		pairs := make([]string, 0)
+		for a := 1; a <= n; a++ {
+			for b := 1; b <= n; b++ {
+				if a != b && rng.Intn(4) == 0 {
+					pairs = append(pairs, fmt.Sprintf("%d|%d", a, b))
+				}
+			}
+		}
+		if len(pairs) == 0 {
+			pairs = append(pairs, "98|99")
+		}
+		rules := parseRules(t, pairs...)
+		pages := rng.Perm(n)
+		for i := range pages {
+			pages[i] += 1
+		}
+
+		want := validOrders(rules, slices.Clone(pages))
+		count, err := rules.CountOrderings(pages)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != len(want) {
+			t.Fatalf("rules %v: CountOrderings(%v) = %d, want %d", pairs, pages, count, len(want))
+		}
+
+		got := make([][]int, 0)
+		for order := range rules.Orderings(pages) {
+			got = append(got, slices.Clone(order))
+		}
+		sortOrders(got)
+		sortOrders(want)
+		if !slices.EqualFunc(got, want, slices.Equal) {
+			t.Fatalf("rules %v: Orderings(%v) = %v, want %v", pairs, pages, got, want)
+		}
+
+		wantMiddles := make([]int, 0)
+		for _, page := range pages {
+			for _, order := range want {
+				if order[len(order)/2] == page {
+					wantMiddles = append(wantMiddles, page)
+					break
+				}
+			}
+		}
+		middles, err := rules.MiddlePages(pages)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(want) == 0 {
+			wantMiddles = nil
+		}
+		if !slices.Equal(middles, wantMiddles) {
+			t.Fatalf("rules %v: MiddlePages(%v) = %v, want %v", pairs, pages, middles, wantMiddles)
+		}
+	}
+}
+
+func sortOrders(orders [][]int) {
+	slices.SortFunc(orders, slices.Compare)
+}
+
+func TestCountOrderingsLimits(t *testing.T) {
+	pages := func(n int) []int {
+		p := make([]int, n)
+		for i := range p {
+			p[i] = i + 1
+		}
+		return p
+	}
+	chain := make([]string, 0)
+	for i := 1; i < 23; i++ {
+		chain = append(chain, fmt.Sprintf("%d|%d", i, i+1))
+	}
+
+	tests := []struct {
+		name    string
+		rules   Rules
+		pages   []int
+		want    int
+		wantErr error
+	}{
+		{name: "23 pages in a chain", rules: parseRules(t, chain...), pages: pages(23), want: 1},
+		// 20! fits in an int.
+		{name: "20 free pages", rules: parseRules(t, "98|99"), pages: pages(20), want: 2432902008176640000},
+		{name: "21 free pages overflow", rules: parseRules(t, "98|99"), pages: pages(21), wantErr: checked.ErrOverflow},
+		{name: "too many pages", rules: parseRules(t, "98|99"), pages: pages(MaxCountPages + 1), wantErr: ErrTooManyPages},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rules.CountOrderings(tt.pages)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("CountOrderings() = %d, %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestOrderingsCycleFailsFast would take minutes if the cycle were only found
+// by trying every order of the other pages.
+func TestOrderingsCycleFailsFast(t *testing.T) {
+	rules := parseRules(t, "1|2", "2|1")
+	pages := []int{1, 2, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21}
+	for order := range rules.Orderings(pages) {
+		t.Fatalf("Orderings() yielded %v for cyclic rules", order)
+	}
+	for order := range rules.Orderings([]int{11, 12, 11}) {
+		t.Fatalf("Orderings() yielded %v for an update with a repeated page", order)
+	}
+}
@@ -0,0 +1,242 @@
+package day05
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ruleGraph holds the ordering rules as a graph from each page to the pages
+// that must come after it, along with a set of the rules themselves so that
+// any pair of pages can be checked in constant time.
+type ruleGraph struct {
+	rules map[ordering]struct{}
+	after map[int][]int
+}
+
+func newRuleGraph(orderings []ordering) *ruleGraph {
+	g := &ruleGraph{
+		rules: make(map[ordering]struct{}, len(orderings)),
+		after: make(map[int][]int),
+	}
+	for _, o := range orderings {
+		if _, ok := g.rules[o]; ok {
+			continue
+		}
+		g.rules[o] = struct{}{}
+		g.after[o.before] = append(g.after[o.before], o.after)
+	}
+	return g
+}
+
+// mustPrecede reports whether a rule puts before ahead of after.
+func (g *ruleGraph) mustPrecede(before int, after int) bool {
+	_, ok := g.rules[ordering{before: before, after: after}]
+	return ok
+}
+
+// CycleError is returned when the rules between an update's pages go round in
+// a loop, so that no order satisfies them all.
+type CycleError struct {
+	// Cycle lists the pages in the loop, each one required to come before the
+	// next and the last before the first.
+	Cycle []int
+}
+
+func (e *CycleError) Error() string {
+	pages := make([]string, len(e.Cycle)+1)
+	for i, page := range e.Cycle {
+		pages[i] = strconv.Itoa(page)
+	}
+	pages[len(e.Cycle)] = pages[0]
+	return fmt.Sprintf("ordering rules form a cycle: %s", strings.Join(pages, " before "))
+}
+
+// AmbiguousMiddleError is returned when the rules between an update's pages
+// allow orders with different pages in the middle, so the update has no single
+// middle page to count.
+type AmbiguousMiddleError struct {
+	// Middles are the pages some valid order puts in the middle, in the order
+	// they appear in the update.
+	Middles []int
+}
+
+func (e *AmbiguousMiddleError) Error() string {
+	return fmt.Sprintf("ordering rules allow any of pages %v in the middle", e.Middles)
+}
+
+// pageOrder is an update's pages put in an order that satisfies the rules.
+type pageOrder struct {
+	pages []int
+	// unique is false when the rules leave the order of some pages open, in
+	// which case pages is just one of the valid orders. tied holds the first
+	// pages found that could have gone in either order.
+	unique bool
+	tied   []int
+}
+
+// indexHeap pops the page that came earliest in the original update.
+type indexHeap struct {
+	pages    []int
+	position map[int]int
+}
+
+func (h *indexHeap) Len() int { return len(h.pages) }
+func (h *indexHeap) Less(i, j int) bool {
+	return h.position[h.pages[i]] < h.position[h.pages[j]]
+}
+func (h *indexHeap) Swap(i, j int) { h.pages[i], h.pages[j] = h.pages[j], h.pages[i] }
+func (h *indexHeap) Push(x any)    { h.pages = append(h.pages, x.(int)) }
+func (h *indexHeap) Pop() any {
+	last := h.pages[len(h.pages)-1]
+	h.pages = h.pages[:len(h.pages)-1]
+	return last
+}
+
+// order topologically sorts pages using only the rules between them. When
+// more than one page could go next, the one earliest in pages wins, so pages
+// that are already in a valid order are left alone.
+func (g *ruleGraph) order(pages []int) (pageOrder, error) {
+	position := make(map[int]int, len(pages))
+	for i, page := range pages {
+		if _, ok := position[page]; ok {
+			return pageOrder{}, fmt.Errorf("page %d appears more than once", page)
+		}
+		position[page] = i
+	}
+
+	inDegree := make(map[int]int, len(pages))
+	for _, page := range pages {
+		for _, next := range g.after[page] {
+			if _, ok := position[next]; ok {
+				inDegree[next] += 1
+			}
+		}
+	}
+
+	ready := &indexHeap{position: position}
+	for _, page := range pages {
+		if inDegree[page] == 0 {
+			ready.pages = append(ready.pages, page)
+		}
+	}
+	heap.Init(ready)
+
+	result := pageOrder{pages: make([]int, 0, len(pages)), unique: true}
+	for ready.Len() > 0 {
+		if ready.Len() > 1 && result.unique {
+			result.unique = false
+			result.tied = slices.Clone(ready.pages)
+			slices.SortFunc(result.tied, func(a int, b int) int { return position[a] - position[b] })
+		}
+		page := heap.Pop(ready).(int)
+		result.pages = append(result.pages, page)
+		for _, next := range g.after[page] {
+			if _, ok := position[next]; !ok {
+				continue
+			}
+			inDegree[next] -= 1
+			if inDegree[next] == 0 {
+				heap.Push(ready, next)
+			}
+		}
+	}
+
+	if len(result.pages) < len(pages) {
+		return pageOrder{}, &CycleError{Cycle: g.findCycle(pages, inDegree)}
+	}
+	return result, nil
+}
+
+// middlePages returns the pages some valid order puts in the middle, in the
+// order they appear in pages, which must not repeat or have a cycle in their
+// rules. A page can go anywhere from just after the pages the rules force
+// before it to just before the pages they force after it, as the pages it
+// isn't ordered against can be split either side of it at will.
+func (g *ruleGraph) middlePages(pages []int) []int {
+	inUpdate := make(map[int]bool, len(pages))
+	for _, page := range pages {
+		inUpdate[page] = true
+	}
+	after := make(map[int][]int, len(pages))
+	before := make(map[int][]int, len(pages))
+	for _, page := range pages {
+		for _, next := range g.after[page] {
+			if inUpdate[next] {
+				after[page] = append(after[page], next)
+				before[next] = append(before[next], page)
+			}
+		}
+	}
+
+	// forced counts the pages reachable from page, not counting itself.
+	forced := func(page int, edges map[int][]int) int {
+		seen := map[int]bool{page: true}
+		stack := []int{page}
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, next := range edges[current] {
+				if !seen[next] {
+					seen[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		return len(seen) - 1
+	}
+
+	middle := len(pages) / 2
+	middles := make([]int, 0)
+	for _, page := range pages {
+		earliest := forced(page, before)
+		latest := len(pages) - 1 - forced(page, after)
+		if earliest <= middle && middle <= latest {
+			middles = append(middles, page)
+		}
+	}
+	return middles
+}
+
+// findCycle walks back from a page the sort never reached until it repeats.
+// Every unreached page has an unreached page before it, so this always ends
+// in a loop.
+func (g *ruleGraph) findCycle(pages []int, inDegree map[int]int) []int {
+	unreached := make(map[int]bool)
+	for _, page := range pages {
+		if inDegree[page] > 0 {
+			unreached[page] = true
+		}
+	}
+	before := func(page int) int {
+		for _, candidate := range pages {
+			if unreached[candidate] && g.mustPrecede(candidate, page) {
+				return candidate
+			}
+		}
+		panic(fmt.Sprintf("unreached page %d has nothing unreached before it", page))
+	}
+
+	var page int
+	for _, p := range pages {
+		if unreached[p] {
+			page = p
+			break
+		}
+	}
+	seenAt := make(map[int]int)
+	path := make([]int, 0)
+	for {
+		if i, ok := seenAt[page]; ok {
+			cycle := path[i:]
+			// The walk went backwards, so flip it to read forwards.
+			slices.Reverse(cycle)
+			return cycle
+		}
+		seenAt[page] = len(path)
+		path = append(path, page)
+		page = before(page)
+	}
+}
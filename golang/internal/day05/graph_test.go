@@ -0,0 +1,112 @@
+package day05
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       []string
+		pages       []int
+		want        []int
+		wantMiddles []int
+	}{
+		{name: "one order", rules: []string{"1|2", "2|3"}, pages: []int{3, 1, 2}, want: []int{1, 2, 3}},
+		{
+			// 3 has two pages forced either side of it, so only 1 and 2, and
+			// 4 and 5, are tied.
+			name:  "ties away from the middle",
+			rules: []string{"1|3", "2|3", "3|4", "3|5"},
+			pages: []int{4, 5, 3, 2, 1},
+			want:  []int{2, 1, 3, 4, 5},
+		},
+		{
+			name:  "even length",
+			rules: []string{"1|3", "2|3", "3|4"},
+			pages: []int{4, 3, 2, 1},
+			want:  []int{2, 1, 3, 4},
+		},
+		{name: "ties in the middle", rules: []string{"1|2"}, pages: []int{3, 2, 1}, wantMiddles: []int{3, 2, 1}},
+		{name: "two candidates", rules: []string{"1|2", "1|3", "2|4", "3|4"}, pages: []int{4, 3, 2, 1}, wantMiddles: []int{3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := parseRules(t, tt.rules...)
+			update := pageUpdate{pageNums: slices.Clone(tt.pages)}
+			_, err := update.sort(rules.graph)
+
+			var ambiguous *AmbiguousMiddleError
+			if tt.wantMiddles != nil {
+				if !errors.As(err, &ambiguous) || !slices.Equal(ambiguous.Middles, tt.wantMiddles) {
+					t.Errorf("sort(%v) error = %v, want middles %v", tt.pages, err, tt.wantMiddles)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sort(%v) error = %v", tt.pages, err)
+			}
+			if !slices.Equal(update.pageNums, tt.want) {
+				t.Errorf("sort(%v) = %v, want %v", tt.pages, update.pageNums, tt.want)
+			}
+		})
+	}
+}
+
+// TestMiddlePagesBruteForce checks the forced before and after counts
+// against the subset DP in MiddlePages, on random rules without cycles.
+func TestMiddlePagesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for range 500 {
+		n := 1 + rng.Intn(9)
+		// Rules only go forwards in rank, so they can't form a cycle.
+		rank := rng.Perm(n)
+		pairs := make([]string, 0)
+		for a := range n {
+			for b := range n {
+				if rank[a] < rank[b] && rng.Intn(3) == 0 {
+					pairs = append(pairs, fmt.Sprintf("%d|%d", a+1, b+1))
+				}
+			}
+		}
+		if len(pairs) == 0 {
+			pairs = append(pairs, "98|99")
+		}
+		rules := parseRules(t, pairs...)
+		pages := make([]int, n)
+		for i, page := range rng.Perm(n) {
+			pages[i] = page + 1
+		}
+
+		want, err := rules.MiddlePages(pages)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rules.graph.middlePages(pages); !slices.Equal(got, want) {
+			t.Fatalf("middlePages(%v) with rules %v = %v, want %v", pages, pairs, got, want)
+		}
+	}
+}
+
+func TestCheckUpdatesTies(t *testing.T) {
+	input := "1|3\n2|3\n3|4\n3|5\n1|2\n\n4,5,3,2,1\n5,4,3\n"
+	reports, err := CheckUpdates(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := reports[0]
+	if !slices.Equal(report.Fixed, []int{1, 2, 3, 4, 5}) || !slices.Equal(report.Tied, []int{4, 5}) || report.Error != "" {
+		t.Errorf("update 1 fixed %v with ties %v and error %q, want [1 2 3 4 5] with ties [4 5]",
+			report.Fixed, report.Tied, report.Error)
+	}
+	report = reports[1]
+	if report.Fixed != nil || report.Tied != nil || report.Error != "ordering rules allow any of pages [5 4] in the middle" {
+		t.Errorf("update 2 fixed %v with ties %v and error %q, want it left unfixed with pages 5 and 4 in the middle",
+			report.Fixed, report.Tied, report.Error)
+	}
+}
@@ -0,0 +1,155 @@
+package day05
+
+import (
+	"slices"
+)
+
+// Violation is a rule Before|After that an update breaks by putting After
+// ahead of Before. The indexes are positions in the update.
+type Violation struct {
+	Before      int `json:"before"`
+	After       int `json:"after"`
+	BeforeIndex int `json:"before_index"`
+	AfterIndex  int `json:"after_index"`
+}
+
+// Move takes Page out of the update from index From and puts it back in so
+// that it ends up at index To. From is counted in the update as it stands
+// just before the move and To in the update just after it, so taking the page
+// out and inserting it at To replays the move.
+type Move struct {
+	Page int `json:"page"`
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// UpdateReport explains whether an update is in order and, if not, why and
+// how to fix it.
+type UpdateReport struct {
+	// Update counts updates from 1 in the order they appear in the input.
+	Update     int         `json:"update"`
+	Pages      []int       `json:"pages"`
+	Valid      bool        `json:"valid"`
+	Violations []Violation `json:"violations,omitempty"`
+	// Fixed is the order the topological sort settles on, and Moves the
+	// fewest moves that turn Pages into it. Both are left out when the rules
+	// allow no order or leave the middle page open, with Error saying why.
+	Fixed []int  `json:"fixed,omitempty"`
+	Moves []Move `json:"moves,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Tied is set when Fixed is only one of several valid orders, to the first
+	// pages found that the rules don't order between. The sort keeps those in
+	// the order they had in the update.
+	Tied []int `json:"tied,omitempty"`
+}
+
+// CheckUpdates reports on every update in input.
+func CheckUpdates(input string) ([]UpdateReport, error) {
+	orderings, updates, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	rules := newRuleGraph(orderings)
+
+	reports := make([]UpdateReport, len(updates))
+	for i, update := range updates {
+		report := UpdateReport{
+			Update:     i + 1,
+			Pages:      update.pageNums,
+			Violations: rules.violations(update.pageNums),
+		}
+		report.Valid = len(report.Violations) == 0
+		if !report.Valid {
+			fixed := update
+			if order, err := fixed.sort(rules); err != nil {
+				report.Error = err.Error()
+			} else {
+				report.Fixed = fixed.pageNums
+				report.Moves = movesBetween(update.pageNums, fixed.pageNums)
+				report.Tied = order.tied
+			}
+		}
+		reports[i] = report
+	}
+	return reports, nil
+}
+
+// violations lists every pair of pages in the wrong order for a rule.
+func (g *ruleGraph) violations(pages []int) []Violation {
+	violations := make([]Violation, 0)
+	for i, earlier := range pages {
+		for j := i + 1; j < len(pages); j++ {
+			if g.mustPrecede(pages[j], earlier) {
+				violations = append(violations, Violation{
+					Before:      pages[j],
+					After:       earlier,
+					BeforeIndex: j,
+					AfterIndex:  i,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// movesBetween returns the fewest moves that turn from into to, which must
+// hold the same pages. The longest run of pages already in the right relative
+// order stays put and every other page is moved once, in the order they come
+// in to, to sit just after the page that precedes it there.
+func movesBetween(from []int, to []int) []Move {
+	targetIndex := make(map[int]int, len(to))
+	for i, page := range to {
+		targetIndex[page] = i
+	}
+	stays := longestIncreasingRun(from, targetIndex)
+
+	current := slices.Clone(from)
+	moves := make([]Move, 0)
+	for i, page := range to {
+		if stays[page] {
+			continue
+		}
+		fromIndex := slices.Index(current, page)
+		current = slices.Delete(current, fromIndex, fromIndex+1)
+		toIndex := 0
+		if i > 0 {
+			toIndex = slices.Index(current, to[i-1]) + 1
+		}
+		current = slices.Insert(current, toIndex, page)
+		moves = append(moves, Move{Page: page, From: fromIndex, To: toIndex})
+	}
+	return moves
+}
+
+// longestIncreasingRun picks the most pages of from that are already in the
+// same relative order as in the target, by patience sorting their target
+// indexes.
+func longestIncreasingRun(from []int, targetIndex map[int]int) map[int]bool {
+	// tails[k] is the index into from of the smallest possible last page of
+	// an increasing run of length k+1.
+	tails := make([]int, 0)
+	previous := make([]int, len(from))
+	for i, page := range from {
+		k, _ := slices.BinarySearchFunc(tails, targetIndex[page], func(tail int, target int) int {
+			return targetIndex[from[tail]] - target
+		})
+		previous[i] = -1
+		if k > 0 {
+			previous[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	stays := make(map[int]bool)
+	if len(tails) == 0 {
+		return stays
+	}
+	for i := tails[len(tails)-1]; i != -1; i = previous[i] {
+		stays[from[i]] = true
+	}
+	return stays
+}
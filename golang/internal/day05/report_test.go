@@ -0,0 +1,69 @@
+package day05
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestCheckUpdatesMoves(t *testing.T) {
+	reports, err := CheckUpdates(readExample(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		update    int
+		wantFixed []int
+		wantMoves []Move
+	}{
+		{update: 1},
+		{update: 4, wantFixed: []int{97, 75, 47, 61, 53}, wantMoves: []Move{{Page: 75, From: 0, To: 1}}},
+		{update: 5, wantFixed: []int{61, 29, 13}, wantMoves: []Move{{Page: 13, From: 1, To: 2}}},
+		{
+			update:    6,
+			wantFixed: []int{97, 75, 47, 29, 13},
+			wantMoves: []Move{{Page: 29, From: 3, To: 4}, {Page: 13, From: 1, To: 4}},
+		},
+	}
+	for _, tt := range tests {
+		report := reports[tt.update-1]
+		if report.Valid != (tt.wantFixed == nil) {
+			t.Errorf("update %d valid = %v, want %v", tt.update, report.Valid, tt.wantFixed == nil)
+		}
+		if !slices.Equal(report.Fixed, tt.wantFixed) || !slices.Equal(report.Moves, tt.wantMoves) {
+			t.Errorf("update %d fixed %v with %+v, want %v with %+v",
+				tt.update, report.Fixed, report.Moves, tt.wantFixed, tt.wantMoves)
+		}
+	}
+}
+
+// TestMovesReplay checks what the indexes of a Move refer to: From is where
+// the page is before the move and To where it is after it.
+func TestMovesReplay(t *testing.T) {
+	reports, err := CheckUpdates(readExample(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, report := range reports {
+		pages := slices.Clone(report.Pages)
+		for _, move := range report.Moves {
+			if pages[move.From] != move.Page {
+				t.Fatalf("update %d: %+v, but page %d is at index %d", report.Update, move, pages[move.From], move.From)
+			}
+			pages = slices.Delete(pages, move.From, move.From+1)
+			pages = slices.Insert(pages, move.To, move.Page)
+		}
+		if !report.Valid && !slices.Equal(pages, report.Fixed) {
+			t.Errorf("update %d: replaying %+v on %v gives %v, want %v", report.Update, report.Moves, report.Pages, pages, report.Fixed)
+		}
+	}
+}
+
+func readExample(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
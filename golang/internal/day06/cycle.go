@@ -0,0 +1,180 @@
+package day06
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// facingChars are the guard characters for each facing, in facing order.
+const facingChars = "^>v<"
+
+// Cell is a cell of the floor plan.
+type Cell struct {
+	Row int
+	Col int
+}
+
+// State is a cell the guard stands on and the way it faces, as one of the
+// guard characters ^, >, v or <.
+type State struct {
+	Cell
+	Facing string
+}
+
+func newState(coord coordinateWithFacing) State {
+	return State{Cell: Cell{Row: coord.row, Col: coord.col}, Facing: facingChars[coord.facing : coord.facing+1]}
+}
+
+// Loop is the cycle a guard is trapped in by one extra obstruction.
+type Loop struct {
+	Obstruction Cell
+	// States runs around the loop one step at a time, from the first state
+	// the guard repeats to just before it reaches that state again. Each
+	// state's facing is the way the guard stepped onto the cell.
+	States []State
+	// Turns are the states after which the guard turns before its next step.
+	Turns []State
+	// Trapped is set when the obstruction boxes the guard in so that it never
+	// steps again. States then holds only the state it is stuck in.
+	Trapped bool
+}
+
+// Len is the number of steps it takes the guard to go round the loop once.
+func (l Loop) Len() int {
+	return len(l.States)
+}
+
+type tracedLoop struct {
+	cycle   []coordinateWithFacing
+	trapped bool
+}
+
+// traceLoop walks the guard with an extra obstacle at obstruction until it
+// repeats a state, returning the states from the first repeated one back
+// round to it, or false if the guard walks off the map instead. A guard that
+// gets trapped is returned as a loop of the one state it is stuck in.
+func traceLoop(floorPlan [][]string, obstruction coordinate) (tracedLoop, bool, error) {
+	previous := floorPlan[obstruction.row][obstruction.col]
+	floorPlan[obstruction.row][obstruction.col] = "O"
+	defer func() { floorPlan[obstruction.row][obstruction.col] = previous }()
+
+	game, err := createGameMap(floorPlan)
+	if err != nil {
+		return tracedLoop{}, false, err
+	}
+	start := coordinateWithFacing{game.guardPosition.row, game.guardPosition.col, game.guardFacing}
+	states := []coordinateWithFacing{start}
+	firstSeen := map[coordinateWithFacing]int{start: 0}
+	for {
+		state, seenBefore, offMap, err := game.walkGuard()
+		var trapped *TrappedGuardError
+		if errors.As(err, &trapped) {
+			return tracedLoop{cycle: []coordinateWithFacing{state}, trapped: true}, true, nil
+		}
+		if err != nil {
+			return tracedLoop{}, false, err
+		}
+		if offMap {
+			return tracedLoop{}, false, nil
+		}
+		if seenBefore {
+			return tracedLoop{cycle: states[firstSeen[state]:]}, true, nil
+		}
+		firstSeen[state] = len(states)
+		states = append(states, state)
+	}
+}
+
+func newLoop(obstruction coordinate, traced tracedLoop) Loop {
+	cycle := traced.cycle
+	loop := Loop{
+		Obstruction: Cell{Row: obstruction.row, Col: obstruction.col},
+		States:      make([]State, len(cycle)),
+		Turns:       make([]State, 0),
+		Trapped:     traced.trapped,
+	}
+	for i, state := range cycle {
+		loop.States[i] = newState(state)
+		if cycle[(i+1)%len(cycle)].facing != state.facing {
+			loop.Turns = append(loop.Turns, loop.States[i])
+		}
+	}
+	return loop
+}
+
+// FindLoops returns the loop the guard is trapped in for every obstruction
+// that causes one, in the order the guard first reaches each obstruction.
+func FindLoops(input string) ([]Loop, error) {
+	game, err := parseGameMap(input)
+	if err != nil {
+		return nil, err
+	}
+	obstructions := figureOutLoopingObstructions(game)
+
+	loops := make([]Loop, 0, len(obstructions))
+	for _, obstruction := range obstructions {
+		traced, ok, err := traceLoop(game.floorPlan, obstruction)
+		if err != nil {
+			return nil, fmt.Errorf("obstruction at %d,%d: %w", obstruction.row, obstruction.col, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("obstruction at %d,%d was found to cause a loop, but the guard walks off the map when tracing it", obstruction.row, obstruction.col)
+		}
+		loops = append(loops, newLoop(obstruction, traced))
+	}
+	return loops, nil
+}
+
+// RenderLoop draws the floor plan with the loop marked as in the puzzle: '|'
+// where the guard only walks north or south, '-' where it only walks east or
+// west and '+' where it does both, with the obstruction as 'O'. A trapped
+// guard is drawn as '+' on the cell it is stuck in.
+func RenderLoop(input string, loop Loop) (string, error) {
+	floorPlan, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	vertical := make(map[coordinate]bool)
+	horizontal := make(map[coordinate]bool)
+	mark := func(coord coordinate, facing string) {
+		if facing == "^" || facing == "v" {
+			vertical[coord] = true
+		} else {
+			horizontal[coord] = true
+		}
+	}
+	for i, state := range loop.States {
+		coord := coordinate{state.Row, state.Col}
+		// A cell is walked out of in the next state's facing, which differs
+		// from the way it was walked into at a turn.
+		mark(coord, state.Facing)
+		mark(coord, loop.States[(i+1)%len(loop.States)].Facing)
+		if loop.Trapped {
+			vertical[coord], horizontal[coord] = true, true
+		}
+	}
+
+	var sb strings.Builder
+	for row := range floorPlan {
+		for col, char := range floorPlan[row] {
+			coord := coordinate{row, col}
+			switch {
+			case loop.Obstruction == Cell{Row: row, Col: col}:
+				sb.WriteString("O")
+			case isGuardChar(char):
+				sb.WriteString(char)
+			case vertical[coord] && horizontal[coord]:
+				sb.WriteString("+")
+			case vertical[coord]:
+				sb.WriteString("|")
+			case horizontal[coord]:
+				sb.WriteString("-")
+			default:
+				sb.WriteString(char)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,105 @@
+package day06
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindLoops(t *testing.T) {
+	loops, err := FindLoops(readExample(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	turns := func(states ...State) []State { return states }
+	want := []struct {
+		obstruction Cell
+		length      int
+		turns       []State
+	}{
+		{Cell{6, 3}, 18, turns(State{Cell{1, 4}, "^"}, State{Cell{1, 8}, ">"}, State{Cell{6, 8}, "v"}, State{Cell{6, 4}, "<"})},
+		{Cell{7, 6}, 12, turns(State{Cell{6, 2}, "<"}, State{Cell{4, 2}, "^"}, State{Cell{4, 6}, ">"}, State{Cell{6, 6}, "v"})},
+		{Cell{8, 3}, 34, turns(
+			State{Cell{1, 4}, "^"}, State{Cell{1, 8}, ">"}, State{Cell{6, 8}, "v"}, State{Cell{6, 2}, "<"},
+			State{Cell{4, 2}, "^"}, State{Cell{4, 6}, ">"}, State{Cell{8, 6}, "v"}, State{Cell{8, 4}, "<"},
+		)},
+		{Cell{8, 1}, 16, turns(State{Cell{4, 2}, "^"}, State{Cell{4, 6}, ">"}, State{Cell{8, 6}, "v"}, State{Cell{8, 2}, "<"})},
+		{Cell{7, 7}, 12, turns(State{Cell{8, 6}, "v"}, State{Cell{8, 1}, "<"}, State{Cell{7, 1}, "^"}, State{Cell{7, 6}, ">"})},
+		{Cell{9, 7}, 14, turns(State{Cell{8, 1}, "<"}, State{Cell{7, 1}, "^"}, State{Cell{7, 7}, ">"}, State{Cell{8, 7}, "v"})},
+	}
+	if len(loops) != len(want) {
+		t.Fatalf("FindLoops() found %d loops, want %d", len(loops), len(want))
+	}
+	for i, w := range want {
+		loop := loops[i]
+		if loop.Obstruction != w.obstruction || loop.Len() != w.length || loop.Trapped {
+			t.Errorf("loop %d is obstruction %v of length %d, trapped %v, want %v of length %d",
+				i, loop.Obstruction, loop.Len(), loop.Trapped, w.obstruction, w.length)
+		}
+		if !slices.Equal(loop.Turns, w.turns) {
+			t.Errorf("loop %d turns = %v, want %v", i, loop.Turns, w.turns)
+		}
+		// Each state is one step on from the one before, round the loop.
+		for j, state := range loop.States {
+			next := loop.States[(j+1)%len(loop.States)]
+			if distance := abs(next.Row-state.Row) + abs(next.Col-state.Col); distance != 1 {
+				t.Errorf("loop %d goes from %v to %v in one step", i, state, next)
+				break
+			}
+		}
+	}
+}
+
+func abs(n int) int {
+	return max(n, -n)
+}
+
+func TestRenderLoop(t *testing.T) {
+	input := readExample(t)
+	loops, err := FindLoops(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := RenderLoop(input, loops[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"....#.....\n" +
+		"....+---+#\n" +
+		"....|...|.\n" +
+		"..#.|...|.\n" +
+		"....|..#|.\n" +
+		"....|...|.\n" +
+		".#.O^---+.\n" +
+		"........#.\n" +
+		"#.........\n" +
+		"......#...\n"
+	if got != want {
+		t.Errorf("RenderLoop() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFindLoopsTrapped(t *testing.T) {
+	input := "#.#\n#^#\n###\n"
+	loops, err := FindLoops(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Loop{
+		Obstruction: Cell{0, 1},
+		States:      []State{{Cell{1, 1}, "^"}},
+		Turns:       []State{},
+		Trapped:     true,
+	}
+	if len(loops) != 1 || loops[0].Obstruction != want.Obstruction || !loops[0].Trapped ||
+		!slices.Equal(loops[0].States, want.States) || len(loops[0].Turns) != 0 {
+		t.Fatalf("FindLoops() = %+v, want [%+v]", loops, want)
+	}
+	got, err := RenderLoop(input, loops[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "#O#\n#^#\n###\n"; got != want {
+		t.Errorf("RenderLoop() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,314 @@
+// Package day06 solves day 6, Guard Gallivant.
+package day06
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/samber/lo"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type coordinate struct {
+	row, col int
+}
+
+type coordinateWithFacing struct {
+	row, col, facing int
+}
+
+func (coord coordinateWithFacing) getCoordinate() coordinate {
+	return coordinate{coord.row, coord.col}
+}
+
+const (
+	north = iota
+	east
+	south
+	west
+)
+
+// ErrNoGuard is returned for a floor plan without a guard on it.
+var ErrNoGuard = errors.New("floor plan has no guard")
+
+// ErrMultipleGuards is returned when solving the puzzle for a floor plan with
+// more than one guard on it. Patrol is the way to walk several guards.
+var ErrMultipleGuards = errors.New("floor plan has more than one guard")
+
+// InvalidFacingError is returned when a guard's facing isn't one of ^, >, v
+// or <.
+type InvalidFacingError struct {
+	Char string
+}
+
+func (e *InvalidFacingError) Error() string {
+	return fmt.Sprintf("invalid guard facing %q", e.Char)
+}
+
+// MalformedMapError is returned for a floor plan that isn't a rectangle.
+type MalformedMapError struct {
+	Row    int
+	Reason string
+}
+
+func (e *MalformedMapError) Error() string {
+	return fmt.Sprintf("malformed floor plan at row %d: %s", e.Row, e.Reason)
+}
+
+// TrappedGuardError is returned when a guard turns every way it can without
+// finding a cell it can step into. Such a guard stays where it is forever,
+// so it counts as looping.
+type TrappedGuardError struct {
+	Guard State
+}
+
+func (e *TrappedGuardError) Error() string {
+	return fmt.Sprintf("guard at %d,%d is trapped with nowhere to step", e.Guard.Row, e.Guard.Col)
+}
+
+func isGuardChar(char string) bool {
+	if slices.Contains([]string{"^", ">", "v", "<"}, char) {
+		return true
+	}
+	return false
+}
+
+func facingFromChar(facingChar string) (int, error) {
+	if facingChar == "^" {
+		return north, nil
+	}
+	if facingChar == ">" {
+		return east, nil
+	}
+	if facingChar == "v" {
+		return south, nil
+	}
+	if facingChar == "<" {
+		return west, nil
+	}
+	return -1, &InvalidFacingError{Char: facingChar}
+}
+
+type gameMap struct {
+	floorPlan     [][]string
+	guardPosition coordinate
+	guardFacing   int
+	behaviour     Behaviour
+	turnsTaken    int
+
+	seenGuardPositions               map[coordinateWithFacing]struct{}
+	seenGuardPositionsIgnoringFacing map[coordinate]struct{}
+}
+
+func (gm *gameMap) isObstacle(coord coordinate) bool {
+	if gm.floorPlan[coord.row][coord.col] == "#" {
+		return true
+	}
+	if gm.floorPlan[coord.row][coord.col] == "O" && !gm.behaviour.IgnoreO {
+		return true
+	}
+	return false
+}
+
+func (gm *gameMap) isOffMap(coord coordinate) bool {
+	if coord.row < 0 || coord.col < 0 {
+		return true
+	}
+	if coord.row >= len(gm.floorPlan) || coord.col >= len(gm.floorPlan[0]) {
+		return true
+	}
+	return false
+}
+
+func (gm *gameMap) changeGuardFacing() {
+	turnLeft := gm.behaviour.Turn == TurnLeft || (gm.behaviour.Turn == TurnAlternating && gm.turnsTaken%2 == 1)
+	gm.turnsTaken += 1
+	if turnLeft {
+		gm.guardFacing -= 1
+		if gm.guardFacing < north {
+			gm.guardFacing = west
+		}
+		return
+	}
+	gm.guardFacing += 1
+	if gm.guardFacing > west {
+		gm.guardFacing = north
+	}
+}
+
+func (gm *gameMap) walkGuard() (coordinateWithFacing, bool, bool, error) {
+	var nextMoveCandidate coordinateWithFacing
+	hasValidNextMove := false
+	rotationCount := 0
+	for !hasValidNextMove {
+		if rotationCount >= 4 {
+			trapped := coordinateWithFacing{gm.guardPosition.row, gm.guardPosition.col, gm.guardFacing}
+			return trapped, false, false, &TrappedGuardError{Guard: newState(trapped)}
+		}
+
+		switch gm.guardFacing {
+		case north:
+			nextMoveCandidate = coordinateWithFacing{gm.guardPosition.row - 1, gm.guardPosition.col, gm.guardFacing}
+		case east:
+			nextMoveCandidate = coordinateWithFacing{gm.guardPosition.row, gm.guardPosition.col + 1, gm.guardFacing}
+		case south:
+			nextMoveCandidate = coordinateWithFacing{gm.guardPosition.row + 1, gm.guardPosition.col, gm.guardFacing}
+		case west:
+			nextMoveCandidate = coordinateWithFacing{gm.guardPosition.row, gm.guardPosition.col - 1, gm.guardFacing}
+		default:
+			return nextMoveCandidate, false, false, &InvalidFacingError{Char: strconv.Itoa(gm.guardFacing)}
+		}
+
+		if gm.isOffMap(nextMoveCandidate.getCoordinate()) {
+			return nextMoveCandidate, false, true, nil
+		}
+
+		if gm.isObstacle(nextMoveCandidate.getCoordinate()) {
+			gm.changeGuardFacing()
+			rotationCount += 1
+		} else {
+			hasValidNextMove = true
+		}
+	}
+
+	gm.guardPosition = nextMoveCandidate.getCoordinate()
+	_, seenMoveBefore := gm.seenGuardPositions[nextMoveCandidate]
+	if !seenMoveBefore {
+		gm.seenGuardPositions[nextMoveCandidate] = struct{}{}
+		gm.seenGuardPositionsIgnoringFacing[nextMoveCandidate.getCoordinate()] = struct{}{}
+	}
+	return nextMoveCandidate, seenMoveBefore, false, nil
+
+}
+
+func (gm *gameMap) printMap() {
+	printableMap := make([][]string, len(gm.floorPlan))
+	for row := range gm.floorPlan {
+		printableMap[row] = make([]string, len(gm.floorPlan[row]))
+		for col := range gm.floorPlan[row] {
+			printableMap[row][col] = gm.floorPlan[row][col]
+		}
+	}
+	for k := range gm.seenGuardPositions {
+		printableMap[k.row][k.col] = "X"
+	}
+
+	for row := range printableMap {
+		for col := range printableMap[row] {
+			print(printableMap[row][col])
+		}
+		println()
+	}
+}
+
+func figureOutLoopingObstructions(gm gameMap) []coordinate {
+	return newPatrolGrid(gm).loopingObstructions()
+}
+
+func newGuard(floorPlan [][]string, row int, col int, behaviour Behaviour) (gameMap, error) {
+	guardFacing, err := facingFromChar(floorPlan[row][col])
+	if err != nil {
+		return gameMap{}, fmt.Errorf("guard at %d,%d: %w", row, col, err)
+	}
+	seenGuardPositions := make(map[coordinateWithFacing]struct{})
+	seenGuardPositions[coordinateWithFacing{row, col, guardFacing}] = struct{}{}
+	seenGuardPositionsIgnoringFacing := make(map[coordinate]struct{})
+	seenGuardPositionsIgnoringFacing[coordinate{row, col}] = struct{}{}
+	return gameMap{
+		floorPlan:     floorPlan,
+		guardPosition: coordinate{row, col},
+		guardFacing:   guardFacing,
+		behaviour:     behaviour,
+
+		seenGuardPositions:               seenGuardPositions,
+		seenGuardPositionsIgnoringFacing: seenGuardPositionsIgnoringFacing,
+	}, nil
+}
+
+func createGameMap(floorPlan [][]string) (gameMap, error) {
+	guards := findGuards(floorPlan)
+	switch len(guards) {
+	case 0:
+		return gameMap{}, ErrNoGuard
+	case 1:
+		return newGuard(floorPlan, guards[0].row, guards[0].col, DefaultBehaviour)
+	}
+	return gameMap{}, fmt.Errorf(
+		"%w: at %d,%d and %d,%d",
+		ErrMultipleGuards, guards[0].row, guards[0].col, guards[1].row, guards[1].col,
+	)
+}
+
+func handleLine(line string) []string {
+	return lo.ChunkString(line, 1)
+}
+
+func parseInput(input string) ([][]string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	floorPlan := make([][]string, 0)
+
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		row := handleLine(scanner.Text())
+		if len(floorPlan) > 0 && len(row) != len(floorPlan[0]) {
+			return nil, &MalformedMapError{
+				Row:    len(floorPlan),
+				Reason: fmt.Sprintf("%d cells wide, expected %d", len(row), len(floorPlan[0])),
+			}
+		}
+		floorPlan = append(floorPlan, row)
+	}
+	if len(floorPlan) == 0 {
+		return nil, &MalformedMapError{Row: 0, Reason: "floor plan is empty"}
+	}
+	return floorPlan, scanner.Err()
+}
+
+func parseGameMap(input string) (gameMap, error) {
+	floorPlan, err := parseInput(input)
+	if err != nil {
+		return gameMap{}, err
+	}
+	return createGameMap(floorPlan)
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	game, err := parseGameMap(input)
+	if err != nil {
+		return "", err
+	}
+
+	// A guard walking in a loop never leaves the map, but has been everywhere
+	// it will go by the time it repeats itself. A trapped guard has been
+	// everywhere it will go already.
+	looped, offMap := false, false
+	for !looped && !offMap {
+		_, looped, offMap, err = game.walkGuard()
+		var trapped *TrappedGuardError
+		if errors.As(err, &trapped) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	//game.printMap()
+
+	return strconv.Itoa(len(game.seenGuardPositionsIgnoringFacing)), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	game, err := parseGameMap(input)
+	if err != nil {
+		return "", err
+	}
+
+	loopingObstructions := figureOutLoopingObstructions(game)
+	return strconv.Itoa(len(loopingObstructions)), nil
+}
@@ -0,0 +1,92 @@
+package day06
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseGameMapErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "no guard", input: "..#\n...\n", wantErr: ErrNoGuard},
+		{name: "two guards", input: "^.#\n..<\n", wantErr: ErrMultipleGuards},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseGameMap(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseGameMap() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := (Solver{}).PartOne(tt.input); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PartOne() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Several guards are fine for a patrol, but none still isn't.
+	if _, err := Patrol("^.#\n..<\n", DefaultPatrolConfig); err != nil {
+		t.Errorf("Patrol() with two guards error = %v", err)
+	}
+	if _, err := Patrol("..#\n...\n", DefaultPatrolConfig); !errors.Is(err, ErrNoGuard) {
+		t.Errorf("Patrol() with no guard error = %v, want %v", err, ErrNoGuard)
+	}
+}
+
+func TestMalformedMapError(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantRow int
+	}{
+		{name: "ragged row", input: "...\n.^.\n..\n...\n", wantRow: 2},
+		{name: "ragged after a blank line", input: "...\n\n.^..\n", wantRow: 1},
+		{name: "empty", input: "\n\n", wantRow: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseGameMap(tt.input)
+			var malformed *MalformedMapError
+			if !errors.As(err, &malformed) {
+				t.Fatalf("parseGameMap() error = %v, want a *MalformedMapError", err)
+			}
+			if malformed.Row != tt.wantRow {
+				t.Errorf("MalformedMapError.Row = %d, want %d", malformed.Row, tt.wantRow)
+			}
+		})
+	}
+}
+
+func TestInvalidFacingError(t *testing.T) {
+	// Only guard characters are ever taken for guards when parsing, so put
+	// the guard on some other character directly.
+	floorPlan := [][]string{{".", "x"}}
+	_, err := newGuard(floorPlan, 0, 1, DefaultBehaviour)
+	var invalid *InvalidFacingError
+	if !errors.As(err, &invalid) || invalid.Char != "x" {
+		t.Errorf("newGuard() error = %v, want an *InvalidFacingError for %q", err, "x")
+	}
+}
+
+func TestTrappedGuardError(t *testing.T) {
+	game, err := parseGameMap(".#.\n#>#\n.#.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, err = game.walkGuard()
+	var trapped *TrappedGuardError
+	if !errors.As(err, &trapped) {
+		t.Fatalf("walkGuard() error = %v, want a *TrappedGuardError", err)
+	}
+	// The guard turns right four times, ending up facing the way it started.
+	if want := (State{Cell{1, 1}, ">"}); trapped.Guard != want {
+		t.Errorf("TrappedGuardError.Guard = %+v, want %+v", trapped.Guard, want)
+	}
+
+	// The solver counts a trapped guard as having covered just its own cell.
+	if got, err := (Solver{}).PartOne(".#.\n#>#\n.#.\n"); err != nil || got != "1" {
+		t.Errorf("PartOne() = %q, %v, want 1", got, err)
+	}
+}
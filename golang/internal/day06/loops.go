@@ -0,0 +1,241 @@
+package day06
+
+import (
+	"runtime"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+// patrolGrid is a floor plan laid out for finding loops quickly. Rather than
+// stepping one cell at a time, the guard jumps straight to the next obstacle
+// using tables worked out once up front.
+type patrolGrid struct {
+	rows, cols int
+	blocked    []bool
+	start      coordinateWithFacing
+	// nextObstacle[facing][row*cols+col] is the row, for north and south, or
+	// the column, for east and west, of the first obstacle the guard reaches
+	// walking from that cell in that facing. With nothing in the way it is
+	// just off the map: -1, rows or cols.
+	nextObstacle [4][]int32
+}
+
+func newPatrolGrid(gm gameMap) *patrolGrid {
+	g := &patrolGrid{
+		rows:  len(gm.floorPlan),
+		cols:  len(gm.floorPlan[0]),
+		start: coordinateWithFacing{gm.guardPosition.row, gm.guardPosition.col, gm.guardFacing},
+	}
+	g.blocked = make([]bool, g.rows*g.cols)
+	for row := range gm.floorPlan {
+		for col := range gm.floorPlan[row] {
+			g.blocked[row*g.cols+col] = gm.isObstacle(coordinate{row, col})
+		}
+	}
+
+	for facing := range g.nextObstacle {
+		g.nextObstacle[facing] = make([]int32, g.rows*g.cols)
+	}
+	for row := 0; row < g.rows; row++ {
+		next := int32(-1)
+		for col := 0; col < g.cols; col++ {
+			g.nextObstacle[west][row*g.cols+col] = next
+			if g.blocked[row*g.cols+col] {
+				next = int32(col)
+			}
+		}
+		next = int32(g.cols)
+		for col := g.cols - 1; col >= 0; col-- {
+			g.nextObstacle[east][row*g.cols+col] = next
+			if g.blocked[row*g.cols+col] {
+				next = int32(col)
+			}
+		}
+	}
+	for col := 0; col < g.cols; col++ {
+		next := int32(-1)
+		for row := 0; row < g.rows; row++ {
+			g.nextObstacle[north][row*g.cols+col] = next
+			if g.blocked[row*g.cols+col] {
+				next = int32(row)
+			}
+		}
+		next = int32(g.rows)
+		for row := g.rows - 1; row >= 0; row-- {
+			g.nextObstacle[south][row*g.cols+col] = next
+			if g.blocked[row*g.cols+col] {
+				next = int32(row)
+			}
+		}
+	}
+	return g
+}
+
+// walk moves the guard from state as far as it goes without turning, taking
+// extra as one more obstacle on top of the floor plan. It returns where the
+// guard stops, or false if it walks off the map.
+func (g *patrolGrid) walk(state coordinateWithFacing, extra coordinate) (coordinateWithFacing, bool) {
+	next := int(g.nextObstacle[state.facing][state.row*g.cols+state.col])
+	switch state.facing {
+	case north:
+		if extra.col == state.col && extra.row < state.row && extra.row > next {
+			next = extra.row
+		}
+		state.row = next + 1
+		return state, next >= 0
+	case east:
+		if extra.row == state.row && extra.col > state.col && extra.col < next {
+			next = extra.col
+		}
+		state.col = next - 1
+		return state, next < g.cols
+	case south:
+		if extra.col == state.col && extra.row > state.row && extra.row < next {
+			next = extra.row
+		}
+		state.row = next - 1
+		return state, next < g.rows
+	default:
+		if extra.row == state.row && extra.col < state.col && extra.col > next {
+			next = extra.col
+		}
+		state.col = next + 1
+		return state, next >= 0
+	}
+}
+
+// stateSet is a bitset of guard states, each a cell and facing. Clearing it
+// only touches the words that were set, so one set can be reused across many
+// walks of a large map.
+type stateSet struct {
+	cols    int
+	words   []uint64
+	touched []int
+}
+
+func newStateSet(rows int, cols int) *stateSet {
+	return &stateSet{cols: cols, words: make([]uint64, (rows*cols*4+63)/64)}
+}
+
+// add records state, reporting whether it was already there.
+func (s *stateSet) add(state coordinateWithFacing) bool {
+	bit := (state.row*s.cols+state.col)*4 + state.facing
+	word, mask := bit/64, uint64(1)<<(bit%64)
+	if s.words[word]&mask != 0 {
+		return true
+	}
+	if s.words[word] == 0 {
+		s.touched = append(s.touched, word)
+	}
+	s.words[word] |= mask
+	return false
+}
+
+func (s *stateSet) clear() {
+	for _, word := range s.touched {
+		s.words[word] = 0
+	}
+	s.touched = s.touched[:0]
+}
+
+// loops reports whether the guard, starting from state, ends up walking in a
+// loop once extra is obstructed. Only the states where the guard stops to
+// turn are recorded, as a loop must repeat one of those. A guard boxed in on
+// every side turns on the spot until it repeats itself, so being trapped
+// counts as a loop too.
+func (g *patrolGrid) loops(state coordinateWithFacing, extra coordinate, seen *stateSet) bool {
+	seen.clear()
+	for {
+		stop, onMap := g.walk(state, extra)
+		if !onMap {
+			return false
+		}
+		if seen.add(stop) {
+			return true
+		}
+		stop.facing = (stop.facing + 1) % 4
+		state = stop
+	}
+}
+
+// obstructionCandidate is a cell on the guard's path along with the state the
+// guard is in just before it first steps there.
+type obstructionCandidate struct {
+	cell   coordinate
+	before coordinateWithFacing
+}
+
+// pathCandidates walks the guard's original path one cell at a time, listing
+// every cell it reaches other than its start. An obstacle anywhere else is
+// never walked into, so can't change the route. It also reports whether the
+// guard already loops with no obstacle added.
+func (g *patrolGrid) pathCandidates() ([]obstructionCandidate, bool) {
+	rowSteps := [4]int{north: -1, south: 1}
+	colSteps := [4]int{east: 1, west: -1}
+
+	reached := make([]bool, g.rows*g.cols)
+	reached[g.start.row*g.cols+g.start.col] = true
+	seen := newStateSet(g.rows, g.cols)
+	candidates := make([]obstructionCandidate, 0)
+	state := g.start
+	for !seen.add(state) {
+		row, col := state.row+rowSteps[state.facing], state.col+colSteps[state.facing]
+		if row < 0 || col < 0 || row >= g.rows || col >= g.cols {
+			return candidates, false
+		}
+		if g.blocked[row*g.cols+col] {
+			state.facing = (state.facing + 1) % 4
+			continue
+		}
+		if !reached[row*g.cols+col] {
+			reached[row*g.cols+col] = true
+			candidates = append(candidates, obstructionCandidate{cell: coordinate{row, col}, before: state})
+		}
+		state.row, state.col = row, col
+	}
+	return candidates, true
+}
+
+// loopingObstructions returns every cell on the guard's path where one more
+// obstacle traps it in a loop, in the order the guard first reaches them.
+// Each candidate is checked from just before the guard would first walk into
+// it, since the route up to there is the same with or without it. If the
+// guard loops already, an obstacle off its path leaves it looping, so every
+// such cell is included after those on the path.
+func (g *patrolGrid) loopingObstructions() []coordinate {
+	candidates, alreadyLoops := g.pathCandidates()
+	causesLoop := make([]bool, len(candidates))
+
+	workers := runtime.GOMAXPROCS(0)
+	p := pool.New().WithMaxGoroutines(workers)
+	for worker := range workers {
+		p.Go(func() {
+			seen := newStateSet(g.rows, g.cols)
+			for i := worker; i < len(candidates); i += workers {
+				causesLoop[i] = g.loops(candidates[i].before, candidates[i].cell, seen)
+			}
+		})
+	}
+	p.Wait()
+
+	obstructions := make([]coordinate, 0)
+	for i, candidate := range candidates {
+		if causesLoop[i] {
+			obstructions = append(obstructions, candidate.cell)
+		}
+	}
+
+	if alreadyLoops {
+		onPath := make([]bool, len(g.blocked))
+		onPath[g.start.row*g.cols+g.start.col] = true
+		for _, candidate := range candidates {
+			onPath[candidate.cell.row*g.cols+candidate.cell.col] = true
+		}
+		for cell, blocked := range g.blocked {
+			if !blocked && !onPath[cell] {
+				obstructions = append(obstructions, coordinate{cell / g.cols, cell % g.cols})
+			}
+		}
+	}
+	return obstructions
+}
@@ -0,0 +1,130 @@
+package day06
+
+import (
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// loopingObstructionsBruteForce tries an obstacle on every free cell, walking
+// the guard one step at a time to see whether it loops.
+func loopingObstructionsBruteForce(t *testing.T, gm gameMap) []coordinate {
+	t.Helper()
+	obstructions := make([]coordinate, 0)
+	for row := range gm.floorPlan {
+		for col := range gm.floorPlan[row] {
+			cell := coordinate{row, col}
+			if cell == gm.guardPosition || gm.isObstacle(cell) {
+				continue
+			}
+			_, loops, err := traceLoop(gm.floorPlan, cell)
+			if err != nil {
+				t.Fatalf("obstruction at %d,%d: %v", row, col, err)
+			}
+			if loops {
+				obstructions = append(obstructions, cell)
+			}
+		}
+	}
+	return obstructions
+}
+
+func randomFloorPlan(rng *rand.Rand, rows int, cols int) string {
+	var b strings.Builder
+	guard := rng.Intn(rows * cols)
+	for cell := range rows * cols {
+		switch {
+		case cell == guard:
+			b.WriteByte(facingChars[rng.Intn(len(facingChars))])
+		case rng.Intn(5) == 0:
+			b.WriteByte('#')
+		default:
+			b.WriteByte('.')
+		}
+		if cell%cols == cols-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+func sortedCoordinates(coords []coordinate) []coordinate {
+	sorted := slices.Clone(coords)
+	slices.SortFunc(sorted, func(a, b coordinate) int {
+		if a.row != b.row {
+			return a.row - b.row
+		}
+		return a.col - b.col
+	})
+	return sorted
+}
+
+func TestLoopingObstructions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []coordinate
+	}{
+		{
+			name:  "example",
+			input: readExample(t),
+			want:  []coordinate{{6, 3}, {7, 6}, {7, 7}, {8, 1}, {8, 3}, {9, 7}},
+		},
+		{
+			name:  "walks straight off",
+			input: "...\n.^.\n...\n",
+			want:  []coordinate{},
+		},
+		{
+			name:  "boxed in but for one side",
+			input: ".#.\n#^#\n...\n",
+			want:  []coordinate{{2, 1}},
+		},
+		{
+			name:  "already loops",
+			input: ".#...\n....#\n.^...\n#....\n...#.\n",
+			// Blocking the loop itself lets the guard out; anywhere else it
+			// keeps on looping.
+			want: []coordinate{{0, 0}, {0, 2}, {0, 3}, {0, 4}, {1, 0}, {2, 0}, {2, 2}, {2, 4}, {3, 4}, {4, 0}, {4, 1}, {4, 2}, {4, 4}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gm, err := parseGameMap(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := sortedCoordinates(figureOutLoopingObstructions(gm))
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("loopingObstructions() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoopingObstructionsBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for range 300 {
+		input := randomFloorPlan(rng, 1+rng.Intn(8), 1+rng.Intn(8))
+		gm, err := parseGameMap(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := sortedCoordinates(figureOutLoopingObstructions(gm))
+		want := loopingObstructionsBruteForce(t, gm)
+		if !slices.Equal(got, want) {
+			t.Fatalf("loopingObstructions() = %v, want %v, for\n%s", got, want, input)
+		}
+	}
+}
+
+func readExample(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,326 @@
+package day06
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// TurnRule is which way a guard turns when its way is blocked.
+type TurnRule string
+
+const (
+	TurnRight TurnRule = "right"
+	TurnLeft  TurnRule = "left"
+	// TurnAlternating turns right the first time, left the next, and so on.
+	TurnAlternating TurnRule = "alternating"
+)
+
+// Behaviour is how a guard patrols. The puzzle's guard is DefaultBehaviour.
+type Behaviour struct {
+	Turn TurnRule `json:"turn"`
+	// Step is how many cells the guard walks each tick, turning as often as
+	// it needs to along the way.
+	Step int `json:"step"`
+	// IgnoreO lets the guard walk through 'O' obstacles, which otherwise
+	// block it just as '#' does.
+	IgnoreO bool `json:"ignore_o,omitempty"`
+}
+
+// DefaultBehaviour is the puzzle's guard: it turns right and walks one cell
+// at a time.
+var DefaultBehaviour = Behaviour{Turn: TurnRight, Step: 1}
+
+func (b Behaviour) validate() error {
+	switch b.Turn {
+	case TurnRight, TurnLeft, TurnAlternating:
+	default:
+		return fmt.Errorf("unknown turn %q, expected %s, %s or %s", b.Turn, TurnRight, TurnLeft, TurnAlternating)
+	}
+	if b.Step < 1 {
+		return fmt.Errorf("step must be at least 1, got %d", b.Step)
+	}
+	return nil
+}
+
+// PatrolConfig gives the behaviour of every guard on a floor plan.
+type PatrolConfig struct {
+	Default Behaviour `json:"default"`
+	// Guards holds the behaviour of each guard, in reading order of where
+	// they start. Guards beyond the end of it use Default.
+	Guards []Behaviour `json:"guards,omitempty"`
+}
+
+// DefaultPatrolConfig has every guard behave as the puzzle's does.
+var DefaultPatrolConfig = PatrolConfig{Default: DefaultBehaviour}
+
+// LoadPatrolConfig reads a patrol config from JSON, such as
+// {"default": {"turn": "left"}, "guards": [{"turn": "alternating", "step": 2}]}.
+// Fields left out of the default behaviour are taken from DefaultBehaviour,
+// but each of Guards must be given in full.
+func LoadPatrolConfig(r io.Reader) (PatrolConfig, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	config := DefaultPatrolConfig
+	if err := decoder.Decode(&config); err != nil {
+		return PatrolConfig{}, fmt.Errorf("reading patrol config: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		return PatrolConfig{}, err
+	}
+	return config, nil
+}
+
+func (config PatrolConfig) validate() error {
+	if err := config.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for i, behaviour := range config.Guards {
+		if err := behaviour.validate(); err != nil {
+			return fmt.Errorf("guard %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (config PatrolConfig) behaviour(guard int) Behaviour {
+	if guard < len(config.Guards) {
+		return config.Guards[guard]
+	}
+	return config.Default
+}
+
+// Collision is guards meeting during a patrol, either by stepping onto the
+// same cell or, with Swap set, by walking past each other as they swap cells.
+// Guards are numbered from 1 in reading order of where they start.
+type Collision struct {
+	Tick int
+	// Step is which of the tick's steps, counting from 1, the guards met on.
+	// Every guard takes its first step of a tick at once, then its second,
+	// and so on, waiting once it has walked its Step cells. Guards that stay
+	// together while waiting aren't reported again.
+	Step   int
+	Cell   Cell
+	Guards []int
+	Swap   bool
+}
+
+// GuardReport is how one guard's patrol went.
+type GuardReport struct {
+	Start     State
+	Behaviour Behaviour
+	// Covered is how many distinct cells the guard stood on.
+	Covered int
+	// Ticks is how long the guard walked before leaving the map or, if Left
+	// is false, first repeating itself in a loop.
+	Ticks int
+	Left  bool
+	// Trapped is set for a looping guard that got boxed in and stopped.
+	Trapped bool
+}
+
+// PatrolReport is the outcome of every guard on a floor plan patrolling at
+// once. The patrol goes on until each guard has left the map or is known to
+// be walking in a loop, so collisions after that aren't reported.
+type PatrolReport struct {
+	Guards     []GuardReport
+	Collisions []Collision
+	// Covered is how many distinct cells any guard stood on.
+	Covered int
+	Ticks   int
+}
+
+// patrollingGuard is a guard being simulated, along with what Patrol needs
+// to spot it looping. Its turn count goes into the states it has seen, as a
+// guard alternating its turns only loops if it would turn the same way too.
+type patrollingGuard struct {
+	gameMap
+	seen     map[patrolState]struct{}
+	finished bool
+	report   GuardReport
+}
+
+type patrolState struct {
+	coordinateWithFacing
+	nextTurnLeft bool
+}
+
+func (g *patrollingGuard) state() patrolState {
+	return patrolState{
+		coordinateWithFacing: coordinateWithFacing{g.guardPosition.row, g.guardPosition.col, g.guardFacing},
+		nextTurnLeft:         g.behaviour.Turn == TurnAlternating && g.turnsTaken%2 == 1,
+	}
+}
+
+// findGuards returns the cells of every guard on the floor plan in reading
+// order.
+func findGuards(floorPlan [][]string) []coordinate {
+	guards := make([]coordinate, 0)
+	for row := range floorPlan {
+		for col := range floorPlan[row] {
+			if isGuardChar(floorPlan[row][col]) {
+				guards = append(guards, coordinate{row, col})
+			}
+		}
+	}
+	return guards
+}
+
+// Patrol walks every guard on the floor plan at the same time, a tick at a
+// time, each following its behaviour from config. Guards that walk several
+// cells a tick are checked for meeting after every one of them. Guards don't block each
+// other, but every time they meet is reported as a collision.
+func Patrol(input string, config PatrolConfig) (PatrolReport, error) {
+	if err := config.validate(); err != nil {
+		return PatrolReport{}, err
+	}
+	floorPlan, err := parseInput(input)
+	if err != nil {
+		return PatrolReport{}, err
+	}
+	starts := findGuards(floorPlan)
+	if len(starts) == 0 {
+		return PatrolReport{}, ErrNoGuard
+	}
+
+	guards := make([]*patrollingGuard, len(starts))
+	for i, start := range starts {
+		game, err := newGuard(floorPlan, start.row, start.col, config.behaviour(i))
+		if err != nil {
+			return PatrolReport{}, err
+		}
+		g := &patrollingGuard{gameMap: game, seen: make(map[patrolState]struct{})}
+		g.seen[g.state()] = struct{}{}
+		g.report.Start = newState(g.state().coordinateWithFacing)
+		g.report.Behaviour = g.behaviour
+		guards[i] = g
+	}
+
+	report := PatrolReport{Collisions: make([]Collision, 0)}
+	from := make([]coordinate, len(guards))
+	moved := make([]bool, len(guards))
+	for tick := 1; ; tick++ {
+		walking := false
+		for _, g := range guards {
+			if !g.finished {
+				walking = true
+			}
+		}
+		if !walking {
+			break
+		}
+		report.Ticks = tick
+
+		// Guards walk their cells for the tick in lockstep, so that two
+		// guards passing through each other part way are still seen.
+		for step := 1; ; step++ {
+			stepping := false
+			for i, g := range guards {
+				from[i] = g.guardPosition
+				moved[i] = false
+				if g.report.Left || g.report.Trapped || step > g.behaviour.Step {
+					continue
+				}
+				stepping = true
+				if moved[i], err = g.step(tick); err != nil {
+					return PatrolReport{}, fmt.Errorf("guard %d: %w", i+1, err)
+				}
+			}
+			if !stepping {
+				break
+			}
+			report.Collisions = append(report.Collisions, collisions(tick, step, guards, from, moved)...)
+		}
+	}
+
+	covered := make(map[coordinate]struct{})
+	for _, g := range guards {
+		g.report.Covered = len(g.seenGuardPositionsIgnoringFacing)
+		for coord := range g.seenGuardPositionsIgnoringFacing {
+			covered[coord] = struct{}{}
+		}
+		report.Guards = append(report.Guards, g.report)
+	}
+	report.Covered = len(covered)
+	return report, nil
+}
+
+// step walks the guard one cell during tick, reporting whether it moved. It
+// doesn't move if it walks off the map or gets trapped.
+func (g *patrollingGuard) step(tick int) (bool, error) {
+	_, _, offMap, err := g.walkGuard()
+	var trapped *TrappedGuardError
+	if errors.As(err, &trapped) {
+		if !g.finished {
+			g.finished = true
+			g.report.Ticks = tick
+		}
+		g.report.Trapped = true
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if offMap {
+		g.finished = true
+		g.report.Left = true
+		g.report.Ticks = tick
+		return false, nil
+	}
+	if g.finished {
+		// Already known to be looping, so just keep walking it.
+		return true, nil
+	}
+	if _, ok := g.seen[g.state()]; ok {
+		g.finished = true
+		g.report.Ticks = tick
+		return true, nil
+	}
+	g.seen[g.state()] = struct{}{}
+	return true, nil
+}
+
+// collisions finds the guards still on the map that share a cell after one
+// step of a tick, at least one of them having just stepped there, or that
+// swapped cells during it. from holds where each guard was before the step.
+func collisions(tick int, step int, guards []*patrollingGuard, from []coordinate, moved []bool) []Collision {
+	found := make([]Collision, 0)
+	byCell := make(map[coordinate][]int)
+	arrived := make(map[coordinate]bool)
+	cells := make([]coordinate, 0)
+	for i, g := range guards {
+		if g.report.Left {
+			continue
+		}
+		if len(byCell[g.guardPosition]) == 0 {
+			cells = append(cells, g.guardPosition)
+		}
+		byCell[g.guardPosition] = append(byCell[g.guardPosition], i+1)
+		arrived[g.guardPosition] = arrived[g.guardPosition] || moved[i]
+	}
+	for _, cell := range cells {
+		if len(byCell[cell]) > 1 && arrived[cell] {
+			found = append(found, Collision{Tick: tick, Step: step, Cell: Cell{Row: cell.row, Col: cell.col}, Guards: byCell[cell]})
+		}
+	}
+
+	for i, a := range guards {
+		for j := i + 1; j < len(guards); j++ {
+			b := guards[j]
+			if a.report.Left || b.report.Left || a.guardPosition == b.guardPosition {
+				continue
+			}
+			if a.guardPosition == from[j] && b.guardPosition == from[i] {
+				found = append(found, Collision{
+					Tick:   tick,
+					Step:   step,
+					Cell:   Cell{Row: a.guardPosition.row, Col: a.guardPosition.col},
+					Guards: []int{i + 1, j + 1},
+					Swap:   true,
+				})
+			}
+		}
+	}
+	return found
+}
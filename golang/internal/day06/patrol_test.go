@@ -0,0 +1,228 @@
+package day06
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPatrolCollisions(t *testing.T) {
+	walker := func(step int) Behaviour {
+		return Behaviour{Turn: TurnRight, Step: step}
+	}
+	tests := []struct {
+		name   string
+		input  string
+		config PatrolConfig
+		want   []Collision
+	}{
+		{
+			name:   "meet on a cell",
+			input:  "v\n.\n^\n",
+			config: DefaultPatrolConfig,
+			want: []Collision{
+				{Tick: 1, Step: 1, Cell: Cell{Row: 1, Col: 0}, Guards: []int{1, 2}},
+			},
+		},
+		{
+			name:   "swap cells",
+			input:  "v\n^\n",
+			config: DefaultPatrolConfig,
+			want: []Collision{
+				{Tick: 1, Step: 1, Cell: Cell{Row: 1, Col: 0}, Guards: []int{1, 2}, Swap: true},
+			},
+		},
+		{
+			name:   "meet part way through a tick",
+			input:  "v\n.\n^\n",
+			config: PatrolConfig{Default: walker(2)},
+			want: []Collision{
+				{Tick: 1, Step: 1, Cell: Cell{Row: 1, Col: 0}, Guards: []int{1, 2}},
+			},
+		},
+		{
+			name:   "swap part way through a tick",
+			input:  "v\n.\n.\n^\n",
+			config: PatrolConfig{Default: walker(2)},
+			want: []Collision{
+				{Tick: 1, Step: 2, Cell: Cell{Row: 2, Col: 0}, Guards: []int{1, 2}, Swap: true},
+			},
+		},
+		{
+			name:   "waiting together isn't another collision",
+			input:  "v..\n...\n^.^\n",
+			config: PatrolConfig{Default: walker(1), Guards: []Behaviour{walker(1), walker(1), walker(3)}},
+			want: []Collision{
+				{Tick: 1, Step: 1, Cell: Cell{Row: 1, Col: 0}, Guards: []int{1, 2}},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := Patrol(tc.input, tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(report.Collisions, tc.want) {
+				t.Errorf("Collisions = %+v, want %+v", report.Collisions, tc.want)
+			}
+			for i, g := range report.Guards {
+				if !g.Left {
+					t.Errorf("guard %d didn't leave the map", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestPatrolBehaviours(t *testing.T) {
+	left := Behaviour{Turn: TurnLeft, Step: 1}
+	alternating := Behaviour{Turn: TurnAlternating, Step: 1}
+	ignoreO := Behaviour{Turn: TurnRight, Step: 1, IgnoreO: true}
+	tests := []struct {
+		name   string
+		input  string
+		config PatrolConfig
+		want   []GuardReport
+		ticks  int
+	}{
+		{
+			name:   "turn right",
+			input:  ".#..\n....\n.^..\n",
+			config: DefaultPatrolConfig,
+			want:   []GuardReport{{Start: State{Cell{2, 1}, "^"}, Behaviour: DefaultBehaviour, Covered: 4, Ticks: 4, Left: true}},
+			ticks:  4,
+		},
+		{
+			name:   "turn left",
+			input:  ".#..\n....\n.^..\n",
+			config: PatrolConfig{Default: left},
+			want:   []GuardReport{{Start: State{Cell{2, 1}, "^"}, Behaviour: left, Covered: 3, Ticks: 3, Left: true}},
+			ticks:  3,
+		},
+		{
+			// Turning right both times sends the guard back the way it came.
+			name:   "turn right into a dead end",
+			input:  ".#...\n....#\n.^.#.\n",
+			config: DefaultPatrolConfig,
+			want:   []GuardReport{{Start: State{Cell{2, 1}, "^"}, Behaviour: DefaultBehaviour, Covered: 5, Ticks: 7, Left: true}},
+			ticks:  7,
+		},
+		{
+			name:   "alternate turns",
+			input:  ".#...\n....#\n.^.#.\n",
+			config: PatrolConfig{Default: alternating},
+			want:   []GuardReport{{Start: State{Cell{2, 1}, "^"}, Behaviour: alternating, Covered: 5, Ticks: 5, Left: true}},
+			ticks:  5,
+		},
+		{
+			name:   "blocked by O",
+			input:  ".\nO\n^\n",
+			config: DefaultPatrolConfig,
+			want:   []GuardReport{{Start: State{Cell{2, 0}, "^"}, Behaviour: DefaultBehaviour, Covered: 1, Ticks: 1, Left: true}},
+			ticks:  1,
+		},
+		{
+			name:   "walk through O",
+			input:  ".\nO\n^\n",
+			config: PatrolConfig{Default: ignoreO},
+			want:   []GuardReport{{Start: State{Cell{2, 0}, "^"}, Behaviour: ignoreO, Covered: 3, Ticks: 3, Left: true}},
+			ticks:  3,
+		},
+		{
+			name:   "loop",
+			input:  ".#..\n...#\n#...\n.^#.\n",
+			config: DefaultPatrolConfig,
+			// The guard first repeats itself back at 1,1 facing north.
+			want:  []GuardReport{{Start: State{Cell{3, 1}, "^"}, Behaviour: DefaultBehaviour, Covered: 5, Ticks: 6}},
+			ticks: 6,
+		},
+		{
+			name:   "alternate out of the loop",
+			input:  ".#..\n...#\n#...\n.^#.\n",
+			config: PatrolConfig{Default: alternating},
+			want:   []GuardReport{{Start: State{Cell{3, 1}, "^"}, Behaviour: alternating, Covered: 5, Ticks: 5, Left: true}},
+			ticks:  5,
+		},
+		{
+			name:   "trapped guard beside a walking one",
+			input:  ".#.v\n#^#.\n.#..\n",
+			config: DefaultPatrolConfig,
+			want: []GuardReport{
+				{Start: State{Cell{0, 3}, "v"}, Behaviour: DefaultBehaviour, Covered: 3, Ticks: 3, Left: true},
+				{Start: State{Cell{1, 1}, "^"}, Behaviour: DefaultBehaviour, Covered: 1, Ticks: 1, Trapped: true},
+			},
+			ticks: 3,
+		},
+		{
+			name:   "behaviour per guard",
+			input:  ".#..\n....\n.^.^\n",
+			config: PatrolConfig{Default: DefaultBehaviour, Guards: []Behaviour{left}},
+			want: []GuardReport{
+				{Start: State{Cell{2, 1}, "^"}, Behaviour: left, Covered: 3, Ticks: 3, Left: true},
+				{Start: State{Cell{2, 3}, "^"}, Behaviour: DefaultBehaviour, Covered: 3, Ticks: 3, Left: true},
+			},
+			ticks: 3,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := Patrol(tc.input, tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(report.Guards, tc.want) {
+				t.Errorf("Guards = %+v, want %+v", report.Guards, tc.want)
+			}
+			if report.Ticks != tc.ticks {
+				t.Errorf("Ticks = %d, want %d", report.Ticks, tc.ticks)
+			}
+		})
+	}
+}
+
+func TestLoadPatrolConfig(t *testing.T) {
+	input := `{"default": {"turn": "left"}, "guards": [{"turn": "alternating", "step": 2, "ignore_o": true}]}`
+	config, err := LoadPatrolConfig(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PatrolConfig{
+		Default: Behaviour{Turn: TurnLeft, Step: 1},
+		Guards:  []Behaviour{{Turn: TurnAlternating, Step: 2, IgnoreO: true}},
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("LoadPatrolConfig() = %+v, want %+v", config, want)
+	}
+}
+
+func TestLoadPatrolConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "not json", input: `turn: left`, wantErr: "reading patrol config"},
+		{name: "unknown field", input: `{"default": {"turn": "left", "speed": 2}}`, wantErr: "unknown field"},
+		{name: "unknown turn", input: `{"default": {"turn": "around"}}`, wantErr: `default: unknown turn "around"`},
+		{name: "no steps", input: `{"default": {"step": 0}}`, wantErr: "default: step must be at least 1, got 0"},
+		{name: "guard without a turn", input: `{"guards": [{"step": 1}]}`, wantErr: `guard 1: unknown turn ""`},
+		{
+			name:    "guard without steps",
+			input:   `{"guards": [{"turn": "right", "step": 1}, {"turn": "left"}]}`,
+			wantErr: "guard 2: step must be at least 1, got 0",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadPatrolConfig(strings.NewReader(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("LoadPatrolConfig() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	if _, err := Patrol("^\n", PatrolConfig{Default: Behaviour{Turn: TurnRight}}); err == nil {
+		t.Error("Patrol() with an invalid config error = nil, want an error")
+	}
+}
@@ -0,0 +1,107 @@
+// Package day07 solves day 7, Bridge Repair.
+package day07
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+type equation[T number] struct {
+	target        T
+	candidateNums []T
+}
+
+func handleLine[T number](line string, parseNum func(string) (T, error)) (equation[T], error) {
+	tokens := strings.Fields(line)
+	if len(tokens) < 2 {
+		return equation[T]{}, fmt.Errorf("expected a target and at least one number, got %q", line)
+	}
+
+	// Trim colon from first string.
+	tokens[0] = strings.Trim(tokens[0], ":")
+
+	target, err := parseNum(tokens[0])
+	if err != nil {
+		return equation[T]{}, err
+	}
+
+	candidateNums := make([]T, len(tokens)-1)
+	for i := 1; i < len(tokens); i++ {
+		candidateNum, err := parseNum(tokens[i])
+		if err != nil {
+			return equation[T]{}, err
+		}
+		candidateNums[i-1] = candidateNum
+	}
+
+	return equation[T]{
+		target:        target,
+		candidateNums: candidateNums,
+	}, nil
+}
+
+func parseEquations[T number](input string, parseNum func(string) (T, error)) ([]equation[T], error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	equations := make([]equation[T], 0)
+
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		eq, err := handleLine(scanner.Text(), parseNum)
+		if err != nil {
+			return nil, err
+		}
+		equations = append(equations, eq)
+	}
+	return equations, nil
+}
+
+func parseInput(input string) ([]equation[int], error) {
+	return parseEquations(input, strconv.Atoi)
+}
+
+func parseBigInput(input string) ([]equation[*big.Int], error) {
+	return parseEquations(input, func(token string) (*big.Int, error) {
+		num, ok := new(big.Int).SetString(token, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", token)
+		}
+		return num, nil
+	})
+}
+
+// sumSolvable adds up the targets of the equations ops can solve.
+func sumSolvable(input string, ops []Operator) (string, error) {
+	equations, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	solutionSum := 0
+	for _, solution := range solveAll(equations, ops, intArithmetic) {
+		if solution.Solvable() {
+			var ok bool
+			solutionSum, ok = checked.Add(solutionSum, solution.Target)
+			if !ok {
+				return "", fmt.Errorf("%w: sum of solvable targets", checked.ErrOverflow)
+			}
+		}
+	}
+	return strconv.Itoa(solutionSum), nil
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	return sumSolvable(input, PartOneOperators)
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	return sumSolvable(input, PartTwoOperators)
+}
@@ -0,0 +1,616 @@
+package day07
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+// Operator is a binary operator that equations can be solved with. The
+// solver works backwards from the target, so each operator must be able to
+// undo itself as well as apply, and it must do both with ints and, for the
+// big number mode, with *big.Int.
+type Operator interface {
+	// Symbol is how the operator is written in expressions, such as "+".
+	Symbol() string
+	// Apply returns lhs op rhs, or false if that has no int result, as on
+	// overflow.
+	Apply(lhs int, rhs int) (int, bool)
+	// Inverse returns the lhs for which Apply(lhs, rhs) is result, or false
+	// if there is none. Operators where more than one lhs gives the same
+	// result for some rhs must implement Absorber for that rhs, or
+	// RangeInverter.
+	Inverse(result int, rhs int) (int, bool)
+	ApplyBig(lhs *big.Int, rhs *big.Int) (*big.Int, bool)
+	InverseBig(result *big.Int, rhs *big.Int) (*big.Int, bool)
+}
+
+// Absorber is implemented by operators with an rhs that gives the same
+// result whatever the lhs, as multiplying by zero does. Inverse can't pick a
+// single lhs there, so the solver asks Absorbs first and, if the result
+// matches, takes any value the numbers before can make.
+type Absorber interface {
+	Absorbs(rhs int) (int, bool)
+	AbsorbsBig(rhs *big.Int) (*big.Int, bool)
+}
+
+// RangeInverter is implemented by operators that can be undone for a whole
+// range of results at once, which is how the solver undoes truncating
+// division, where a run of lhs values all give the same result. Every
+// built-in operator implements it, so that the solver can carry a range back
+// through them rather than try each value in it.
+type RangeInverter interface {
+	// InverseRange returns the range of lhs, from lhsLo to lhsHi inclusive,
+	// for which Apply(lhs, rhs) is between lo and hi inclusive, or false if
+	// there is none. For that to be a range, the result must never go up,
+	// or never go down, as lhs does.
+	InverseRange(lo int, hi int, rhs int) (lhsLo int, lhsHi int, ok bool)
+	InverseRangeBig(lo *big.Int, hi *big.Int, rhs *big.Int) (lhsLo *big.Int, lhsHi *big.Int, ok bool)
+}
+
+// nonNegativeOperator is implemented by the built-in operators that never
+// give a negative result from numbers that aren't negative, which lets the
+// solver give up on negative targets early.
+type nonNegativeOperator interface {
+	keepsNonNegative()
+}
+
+type addOperator struct{}
+
+// Add adds.
+var Add Operator = addOperator{}
+
+func (addOperator) Symbol() string    { return "+" }
+func (addOperator) keepsNonNegative() {}
+
+func (addOperator) Apply(lhs int, rhs int) (int, bool) {
+	return checked.Add(lhs, rhs)
+}
+
+func (addOperator) Inverse(result int, rhs int) (int, bool) {
+	return checked.Sub(result, rhs)
+}
+
+func (addOperator) ApplyBig(lhs *big.Int, rhs *big.Int) (*big.Int, bool) {
+	return new(big.Int).Add(lhs, rhs), true
+}
+
+func (addOperator) InverseBig(result *big.Int, rhs *big.Int) (*big.Int, bool) {
+	return new(big.Int).Sub(result, rhs), true
+}
+
+func (addOperator) InverseRange(lo int, hi int, rhs int) (int, int, bool) {
+	lhsLo, ok := checked.Sub(lo, rhs)
+	if !ok {
+		if rhs < 0 {
+			return 0, 0, false
+		}
+		lhsLo = math.MinInt
+	}
+	lhsHi, ok := checked.Sub(hi, rhs)
+	if !ok {
+		if rhs > 0 {
+			return 0, 0, false
+		}
+		lhsHi = math.MaxInt
+	}
+	return lhsLo, lhsHi, true
+}
+
+func (addOperator) InverseRangeBig(lo *big.Int, hi *big.Int, rhs *big.Int) (*big.Int, *big.Int, bool) {
+	return new(big.Int).Sub(lo, rhs), new(big.Int).Sub(hi, rhs), true
+}
+
+type subOperator struct{}
+
+// Sub subtracts.
+var Sub Operator = subOperator{}
+
+func (subOperator) Symbol() string { return "-" }
+
+func (subOperator) Apply(lhs int, rhs int) (int, bool) {
+	return checked.Sub(lhs, rhs)
+}
+
+func (subOperator) Inverse(result int, rhs int) (int, bool) {
+	return checked.Add(result, rhs)
+}
+
+func (subOperator) ApplyBig(lhs *big.Int, rhs *big.Int) (*big.Int, bool) {
+	return new(big.Int).Sub(lhs, rhs), true
+}
+
+func (subOperator) InverseBig(result *big.Int, rhs *big.Int) (*big.Int, bool) {
+	return new(big.Int).Add(result, rhs), true
+}
+
+func (subOperator) InverseRange(lo int, hi int, rhs int) (int, int, bool) {
+	lhsLo, ok := checked.Add(lo, rhs)
+	if !ok {
+		if rhs > 0 {
+			return 0, 0, false
+		}
+		lhsLo = math.MinInt
+	}
+	lhsHi, ok := checked.Add(hi, rhs)
+	if !ok {
+		if rhs < 0 {
+			return 0, 0, false
+		}
+		lhsHi = math.MaxInt
+	}
+	return lhsLo, lhsHi, true
+}
+
+func (subOperator) InverseRangeBig(lo *big.Int, hi *big.Int, rhs *big.Int) (*big.Int, *big.Int, bool) {
+	return new(big.Int).Add(lo, rhs), new(big.Int).Add(hi, rhs), true
+}
+
+type mulOperator struct{}
+
+// Mul multiplies.
+var Mul Operator = mulOperator{}
+
+func (mulOperator) Symbol() string    { return "*" }
+func (mulOperator) keepsNonNegative() {}
+
+func (mulOperator) Apply(lhs int, rhs int) (int, bool) {
+	return checked.Mul(lhs, rhs)
+}
+
+func (mulOperator) Inverse(result int, rhs int) (int, bool) {
+	// Dividing MinInt by -1 overflows.
+	if rhs == 0 || (rhs == -1 && result == math.MinInt) {
+		return 0, false
+	}
+	if result%rhs != 0 {
+		return 0, false
+	}
+	return result / rhs, true
+}
+
+func (mulOperator) ApplyBig(lhs *big.Int, rhs *big.Int) (*big.Int, bool) {
+	return new(big.Int).Mul(lhs, rhs), true
+}
+
+func (mulOperator) InverseBig(result *big.Int, rhs *big.Int) (*big.Int, bool) {
+	if rhs.Sign() == 0 {
+		return nil, false
+	}
+	quotient, remainder := new(big.Int).QuoRem(result, rhs, new(big.Int))
+	return quotient, remainder.Sign() == 0
+}
+
+// InverseRange has no answer for a zero rhs, which Absorbs covers instead.
+func (mulOperator) InverseRange(lo int, hi int, rhs int) (int, int, bool) {
+	var lhsLo, lhsHi int
+	switch {
+	case rhs == 0:
+		return 0, 0, false
+	case rhs == -1:
+		// Negating MinInt overflows.
+		if hi == math.MinInt {
+			return 0, 0, false
+		}
+		lhsLo, lhsHi = -hi, math.MaxInt
+		if lo != math.MinInt {
+			lhsHi = -lo
+		}
+	case rhs > 0:
+		lhsLo, lhsHi = ceilDiv(lo, rhs), floorDiv(hi, rhs)
+	default:
+		lhsLo, lhsHi = ceilDiv(hi, rhs), floorDiv(lo, rhs)
+	}
+	return lhsLo, lhsHi, lhsLo <= lhsHi
+}
+
+func (mulOperator) InverseRangeBig(lo *big.Int, hi *big.Int, rhs *big.Int) (*big.Int, *big.Int, bool) {
+	var lhsLo, lhsHi *big.Int
+	switch rhs.Sign() {
+	case 0:
+		return nil, nil, false
+	case 1:
+		lhsLo, lhsHi = ceilDivBig(lo, rhs), floorDivBig(hi, rhs)
+	default:
+		lhsLo, lhsHi = ceilDivBig(hi, rhs), floorDivBig(lo, rhs)
+	}
+	return lhsLo, lhsHi, lhsLo.Cmp(lhsHi) <= 0
+}
+
+func (mulOperator) Absorbs(rhs int) (int, bool) {
+	return 0, rhs == 0
+}
+
+func (mulOperator) AbsorbsBig(rhs *big.Int) (*big.Int, bool) {
+	return new(big.Int), rhs.Sign() == 0
+}
+
+type divOperator struct{}
+
+// Div divides, truncating towards zero as Go does, so 7 / 2 is 3 and -7 / 2
+// is -3.
+var Div Operator = divOperator{}
+
+func (divOperator) Symbol() string    { return "/" }
+func (divOperator) keepsNonNegative() {}
+
+func (divOperator) Apply(lhs int, rhs int) (int, bool) {
+	// Dividing MinInt by -1 overflows.
+	if rhs == 0 || (rhs == -1 && lhs == math.MinInt) {
+		return 0, false
+	}
+	return lhs / rhs, true
+}
+
+// Inverse only has an answer when the result can come from just one lhs, as
+// when dividing by 1 or -1. Otherwise the solver uses InverseRange.
+func (d divOperator) Inverse(result int, rhs int) (int, bool) {
+	lo, hi, ok := d.InverseRange(result, result, rhs)
+	return lo, ok && lo == hi
+}
+
+// InverseRange finds the lhs values dividing to the ends of the range. A
+// result q comes from q * rhs plus a remainder smaller in size than rhs
+// with the same sign as the lhs, so a positive q starts at q * rhs and a
+// negative one ends there, while zero comes from either side.
+func (d divOperator) InverseRange(lo int, hi int, rhs int) (int, int, bool) {
+	switch {
+	case rhs == 0:
+		return 0, 0, false
+	case rhs == math.MinInt:
+		// Only MinInt itself divides to anything other than 0, which is 1.
+		zero, one := lo <= 0 && hi >= 0, lo <= 1 && hi >= 1
+		switch {
+		case zero && one:
+			return math.MinInt, math.MaxInt, true
+		case zero:
+			return math.MinInt + 1, math.MaxInt, true
+		case one:
+			return math.MinInt, math.MinInt, true
+		}
+		return 0, 0, false
+	case rhs < 0:
+		// Dividing by rhs is dividing by -rhs and negating. Nothing divides
+		// to more than MaxInt, so a range up to -MinInt stops there.
+		if hi == math.MinInt {
+			return 0, 0, false
+		}
+		negLo := math.MaxInt
+		if lo != math.MinInt {
+			negLo = -lo
+		}
+		return d.InverseRange(-hi, negLo, -rhs)
+	}
+
+	// The smallest lhs that divides to lo or more, and the largest that
+	// divides to hi or less. Where they'd be past the ends of an int, every
+	// lhs out that way divides into the range or none does.
+	lhsLo, ok := checked.Mul(lo, rhs)
+	switch {
+	case !ok && lo > 0:
+		return 0, 0, false
+	case !ok:
+		lhsLo = math.MinInt
+	case lo <= 0:
+		if lhsLo, ok = checked.Sub(lhsLo, rhs-1); !ok {
+			lhsLo = math.MinInt
+		}
+	}
+	lhsHi, ok := checked.Mul(hi, rhs)
+	switch {
+	case !ok && hi < 0:
+		return 0, 0, false
+	case !ok:
+		lhsHi = math.MaxInt
+	case hi >= 0:
+		if lhsHi, ok = checked.Add(lhsHi, rhs-1); !ok {
+			lhsHi = math.MaxInt
+		}
+	}
+	return lhsLo, lhsHi, lhsLo <= lhsHi
+}
+
+func (divOperator) ApplyBig(lhs *big.Int, rhs *big.Int) (*big.Int, bool) {
+	if rhs.Sign() == 0 {
+		return nil, false
+	}
+	return new(big.Int).Quo(lhs, rhs), true
+}
+
+func (d divOperator) InverseBig(result *big.Int, rhs *big.Int) (*big.Int, bool) {
+	lo, hi, ok := d.InverseRangeBig(result, result, rhs)
+	return lo, ok && lo.Cmp(hi) == 0
+}
+
+func (d divOperator) InverseRangeBig(lo *big.Int, hi *big.Int, rhs *big.Int) (*big.Int, *big.Int, bool) {
+	switch rhs.Sign() {
+	case 0:
+		return nil, nil, false
+	case -1:
+		return d.InverseRangeBig(new(big.Int).Neg(hi), new(big.Int).Neg(lo), new(big.Int).Neg(rhs))
+	}
+	remainder := new(big.Int).Sub(rhs, big.NewInt(1))
+	lhsLo := new(big.Int).Mul(lo, rhs)
+	if lo.Sign() <= 0 {
+		lhsLo.Sub(lhsLo, remainder)
+	}
+	lhsHi := new(big.Int).Mul(hi, rhs)
+	if hi.Sign() >= 0 {
+		lhsHi.Add(lhsHi, remainder)
+	}
+	return lhsLo, lhsHi, lhsLo.Cmp(lhsHi) <= 0
+}
+
+// concatOperator joins the digits of its operands written in base. The rhs
+// can't be negative, as its minus sign would end up in the middle, while a
+// negative lhs keeps its sign in front, so "-12" || "3" is -123.
+type concatOperator struct {
+	base int
+}
+
+// Concat joins the decimal digits of its operands, so 12 || 345 is 12345.
+var Concat Operator = concatOperator{base: 10}
+
+// ConcatBase returns an operator that joins the digits of its operands
+// written in base, which must be between 2 and 36.
+func ConcatBase(base int) (Operator, error) {
+	if base < 2 || base > 36 {
+		return nil, fmt.Errorf("concat base must be between 2 and 36, got %d", base)
+	}
+	return concatOperator{base: base}, nil
+}
+
+func (c concatOperator) Symbol() string {
+	if c.base == 10 {
+		return "||"
+	}
+	return "||" + strconv.Itoa(c.base)
+}
+
+func (concatOperator) keepsNonNegative() {}
+
+// shift returns what the lhs is multiplied by to make room for the digits of
+// rhs.
+func (c concatOperator) shift(rhs int) (int, bool) {
+	shift := c.base
+	for rest := rhs / c.base; rest > 0; rest /= c.base {
+		var ok bool
+		if shift, ok = checked.Mul(shift, c.base); !ok {
+			return 0, false
+		}
+	}
+	return shift, true
+}
+
+func (c concatOperator) Apply(lhs int, rhs int) (int, bool) {
+	if rhs < 0 {
+		return 0, false
+	}
+	shift, ok := c.shift(rhs)
+	if !ok {
+		return 0, false
+	}
+	shifted, ok := checked.Mul(lhs, shift)
+	if !ok {
+		return 0, false
+	}
+	if lhs < 0 {
+		return checked.Sub(shifted, rhs)
+	}
+	return checked.Add(shifted, rhs)
+}
+
+func (c concatOperator) Inverse(result int, rhs int) (int, bool) {
+	if rhs < 0 {
+		return 0, false
+	}
+	shift, ok := c.shift(rhs)
+	if !ok {
+		return 0, false
+	}
+	if result < 0 {
+		if (result+rhs)%shift != 0 || (result+rhs)/shift >= 0 {
+			return 0, false
+		}
+		return (result + rhs) / shift, true
+	}
+	if result < rhs || (result-rhs)%shift != 0 {
+		return 0, false
+	}
+	return (result - rhs) / shift, true
+}
+
+func (c concatOperator) bigShift(rhs *big.Int) *big.Int {
+	digits := len(rhs.Text(c.base))
+	return new(big.Int).Exp(big.NewInt(int64(c.base)), big.NewInt(int64(digits)), nil)
+}
+
+func (c concatOperator) ApplyBig(lhs *big.Int, rhs *big.Int) (*big.Int, bool) {
+	if rhs.Sign() < 0 {
+		return nil, false
+	}
+	result := new(big.Int).Mul(lhs, c.bigShift(rhs))
+	if lhs.Sign() < 0 {
+		return result.Sub(result, rhs), true
+	}
+	return result.Add(result, rhs), true
+}
+
+func (c concatOperator) InverseBig(result *big.Int, rhs *big.Int) (*big.Int, bool) {
+	if rhs.Sign() < 0 {
+		return nil, false
+	}
+	head := new(big.Int)
+	if result.Sign() < 0 {
+		head.Add(result, rhs)
+	} else {
+		head.Sub(result, rhs)
+	}
+	if result.Sign() >= 0 && head.Sign() < 0 {
+		return nil, false
+	}
+	quotient, remainder := head.QuoRem(head, c.bigShift(rhs), new(big.Int))
+	if remainder.Sign() != 0 || (result.Sign() < 0 && quotient.Sign() >= 0) {
+		return nil, false
+	}
+	return quotient, true
+}
+
+// InverseRange looks for non-negative and negative lhs values separately.
+// The result goes up with lhs either way, and every negative lhs gives a
+// smaller result than any other, so together they make one range.
+func (c concatOperator) InverseRange(lo int, hi int, rhs int) (int, int, bool) {
+	if rhs < 0 {
+		return 0, 0, false
+	}
+	shift, ok := c.shift(rhs)
+	if !ok {
+		return 0, 0, false
+	}
+
+	// A non-negative lhs gives lhs * shift + rhs.
+	posLo, posHi, pos := 0, 0, false
+	if top, ok := checked.Sub(hi, rhs); ok && top >= 0 {
+		posHi, pos = floorDiv(top, shift), true
+		if bottom, ok := checked.Sub(lo, rhs); ok && bottom > 0 {
+			posLo = ceilDiv(bottom, shift)
+		}
+		pos = posLo <= posHi
+	}
+	// A negative lhs gives lhs * shift - rhs.
+	negLo, negHi, neg := 0, -1, false
+	if bottom, ok := checked.Add(lo, rhs); ok && bottom < 0 {
+		negLo, neg = ceilDiv(bottom, shift), true
+		if top, ok := checked.Add(hi, rhs); ok && top < 0 {
+			negHi = floorDiv(top, shift)
+		}
+		neg = negLo <= negHi
+	}
+
+	switch {
+	case pos && neg:
+		return negLo, posHi, true
+	case pos:
+		return posLo, posHi, true
+	case neg:
+		return negLo, negHi, true
+	}
+	return 0, 0, false
+}
+
+func (c concatOperator) InverseRangeBig(lo *big.Int, hi *big.Int, rhs *big.Int) (*big.Int, *big.Int, bool) {
+	if rhs.Sign() < 0 {
+		return nil, nil, false
+	}
+	shift := c.bigShift(rhs)
+
+	posLo := ceilDivBig(new(big.Int).Sub(lo, rhs), shift)
+	if posLo.Sign() < 0 {
+		posLo.SetInt64(0)
+	}
+	posHi := floorDivBig(new(big.Int).Sub(hi, rhs), shift)
+	pos := posLo.Cmp(posHi) <= 0
+
+	negLo := ceilDivBig(new(big.Int).Add(lo, rhs), shift)
+	negHi := floorDivBig(new(big.Int).Add(hi, rhs), shift)
+	if negHi.Sign() >= 0 {
+		negHi.SetInt64(-1)
+	}
+	neg := negLo.Cmp(negHi) <= 0
+
+	switch {
+	case pos && neg:
+		return negLo, posHi, true
+	case pos:
+		return posLo, posHi, true
+	case neg:
+		return negLo, negHi, true
+	}
+	return nil, nil, false
+}
+
+// floorDiv divides a by b rounding down rather than towards zero. It
+// overflows just as a / b does.
+func floorDiv(a int, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// ceilDiv divides a by b rounding up rather than towards zero.
+func ceilDiv(a int, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) == (b < 0) {
+		q++
+	}
+	return q
+}
+
+func floorDivBig(a *big.Int, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() != 0 && (a.Sign() < 0) != (b.Sign() < 0) {
+		q.Sub(q, big.NewInt(1))
+	}
+	return q
+}
+
+func ceilDivBig(a *big.Int, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() != 0 && (a.Sign() < 0) == (b.Sign() < 0) {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// The puzzle's operators for each part.
+var (
+	PartOneOperators = []Operator{Add, Mul}
+	PartTwoOperators = []Operator{Add, Mul, Concat}
+)
+
+// ParseOperators reads a comma separated list of operator symbols: +, -, *,
+// / and ||, with ||N for concatenation in base N.
+func ParseOperators(list string) ([]Operator, error) {
+	ops := make([]Operator, 0)
+	for _, symbol := range strings.Split(list, ",") {
+		symbol = strings.TrimSpace(symbol)
+		var op Operator
+		switch symbol {
+		case "+":
+			op = Add
+		case "-":
+			op = Sub
+		case "*":
+			op = Mul
+		case "/":
+			op = Div
+		case "||":
+			op = Concat
+		default:
+			digits, ok := strings.CutPrefix(symbol, "||")
+			if !ok {
+				return nil, fmt.Errorf("unknown operator %q", symbol)
+			}
+			base, err := strconv.Atoi(digits)
+			if err != nil {
+				return nil, fmt.Errorf("unknown operator %q", symbol)
+			}
+			if op, err = ConcatBase(base); err != nil {
+				return nil, err
+			}
+		}
+		for _, existing := range ops {
+			if existing.Symbol() == op.Symbol() {
+				return nil, fmt.Errorf("operator %q given twice", symbol)
+			}
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
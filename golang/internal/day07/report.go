@@ -0,0 +1,148 @@
+package day07
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+// OperatorSet is a named choice of operators to solve equations with.
+type OperatorSet struct {
+	Name      string
+	Operators []Operator
+}
+
+// ParseOperatorSets reads operator sets separated by ';', each a list of
+// operators as ParseOperators takes, optionally named with "name=", such as
+// "part1=+,*;part2=+,*,||". Sets without a name are named by their list.
+func ParseOperatorSets(spec string) ([]OperatorSet, error) {
+	sets := make([]OperatorSet, 0)
+	names := make(map[string]bool)
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		name, list, named := strings.Cut(part, "=")
+		if !named {
+			name, list = part, part
+		}
+		name = strings.TrimSpace(name)
+		if names[name] {
+			return nil, fmt.Errorf("operator set %q given twice", name)
+		}
+		names[name] = true
+		ops, err := ParseOperators(list)
+		if err != nil {
+			return nil, fmt.Errorf("operator set %q: %w", name, err)
+		}
+		sets = append(sets, OperatorSet{Name: name, Operators: ops})
+	}
+	return sets, nil
+}
+
+// SetResult is how an equation fared with one operator set.
+type SetResult struct {
+	Set        string  `json:"set"`
+	Solvable   bool    `json:"solvable"`
+	Expression string  `json:"expression,omitempty"`
+	ElapsedMS  float64 `json:"elapsed_ms"`
+}
+
+// EquationResult is how an equation fared with every operator set, in the
+// order the sets were given.
+type EquationResult[T number] struct {
+	// Index counts equations from 1 in the order they appear in the input.
+	Index   int         `json:"index"`
+	Target  T           `json:"target"`
+	Results []SetResult `json:"results"`
+}
+
+// CheckOptions configures Check.
+type CheckOptions struct {
+	Sets []OperatorSet
+	// Workers is how many equations are solved at once, or one per CPU if
+	// zero.
+	Workers int
+}
+
+// Check solves every equation in input with each operator set, spreading the
+// equations over a pool of workers, and returns the results in input order.
+func Check(input string, opts CheckOptions) ([]EquationResult[int], error) {
+	equations, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return checkAll(equations, opts, intArithmetic), nil
+}
+
+// CheckBig is Check with exact big integers, as SolveBig is to Solve.
+func CheckBig(input string, opts CheckOptions) ([]EquationResult[*big.Int], error) {
+	equations, err := parseBigInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return checkAll(equations, opts, bigArithmetic), nil
+}
+
+func checkAll[T number](equations []equation[T], opts CheckOptions, arith arithmetic[T]) []EquationResult[T] {
+	results := make([]EquationResult[T], len(equations))
+	forEachEquation(len(equations), opts.Workers, func(i int) {
+		eq := equations[i]
+		result := EquationResult[T]{Index: i + 1, Target: eq.target, Results: make([]SetResult, len(opts.Sets))}
+		for j, set := range opts.Sets {
+			start := time.Now()
+			solution, solvable := eq.solve(set.Operators, arith)
+			result.Results[j] = SetResult{
+				Set:        set.Name,
+				Solvable:   solvable,
+				Expression: solution.Expression(),
+				ElapsedMS:  float64(time.Since(start).Microseconds()) / 1000,
+			}
+		}
+		results[i] = result
+	})
+	return results
+}
+
+// forEachEquation calls check with the index of each of n equations, using at
+// most workers goroutines at a time, or one per CPU if workers is zero.
+func forEachEquation(n int, workers int, check func(i int)) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	p := pool.New().WithMaxGoroutines(workers)
+	for i := range n {
+		p.Go(func() { check(i) })
+	}
+	p.Wait()
+}
+
+// WriteCSV writes results as CSV with one row per equation and, for each
+// operator set, columns for whether it solves the equation, how and how long
+// that took to find out.
+func WriteCSV[T number](w io.Writer, sets []OperatorSet, results []EquationResult[T]) error {
+	writer := csv.NewWriter(w)
+	header := []string{"index", "target"}
+	for _, set := range sets {
+		header = append(header, set.Name+"_solvable", set.Name+"_expression", set.Name+"_elapsed_ms")
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, result := range results {
+		row := []string{strconv.Itoa(result.Index), fmt.Sprint(result.Target)}
+		for _, r := range result.Results {
+			row = append(row, strconv.FormatBool(r.Solvable), r.Expression, strconv.FormatFloat(r.ElapsedMS, 'f', 3, 64))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
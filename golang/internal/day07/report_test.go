@@ -0,0 +1,225 @@
+package day07
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseOperatorSets(t *testing.T) {
+	sets, err := ParseOperatorSets("part1=+,*; +,*,|| ;bin = ||2,-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		name    string
+		symbols []string
+	}{
+		{name: "part1", symbols: []string{"+", "*"}},
+		{name: "+,*,||", symbols: []string{"+", "*", "||"}},
+		{name: "bin", symbols: []string{"||2", "-"}},
+	}
+	if len(sets) != len(want) {
+		t.Fatalf("ParseOperatorSets() = %d sets, want %d", len(sets), len(want))
+	}
+	for i, w := range want {
+		symbols := make([]string, len(sets[i].Operators))
+		for j, op := range sets[i].Operators {
+			symbols[j] = op.Symbol()
+		}
+		if sets[i].Name != w.name || !slices.Equal(symbols, w.symbols) {
+			t.Errorf("set %d = %q %q, want %q %q", i, sets[i].Name, symbols, w.name, w.symbols)
+		}
+	}
+}
+
+func TestParseOperatorSetsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{name: "duplicate name", spec: "a=+;a=*", wantErr: `operator set "a" given twice`},
+		{name: "duplicate unnamed", spec: "+,*;+,*", wantErr: `operator set "+,*" given twice`},
+		{name: "name clashes with a list", spec: "+=*;+", wantErr: `operator set "+" given twice`},
+		{name: "unknown operator", spec: "a=+,%", wantErr: `operator set "a": unknown operator "%"`},
+		{name: "operator given twice", spec: "a=+,+", wantErr: `operator set "a": operator "+" given twice`},
+		{name: "empty set", spec: "a=+;", wantErr: `operator set "": unknown operator ""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOperatorSets(tt.spec)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ParseOperatorSets(%q) error = %v, want %s", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckExample(t *testing.T) {
+	input, err := os.ReadFile("testdata/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sets, err := ParseOperatorSets("part1=+,*;part2=+,*,||")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := Check(string(input), CheckOptions{Sets: sets, Workers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		target int
+		part1  string
+		part2  string
+	}{
+		{190, "10 * 19", "10 * 19"},
+		{3267, "81 * 40 + 27", "81 * 40 + 27"},
+		{83, "", ""},
+		{156, "", "15 || 6"},
+		{7290, "", "6 * 8 || 6 * 15"},
+		{161011, "", ""},
+		{192, "", "17 || 8 + 14"},
+		{21037, "", ""},
+		{292, "11 + 6 * 16 + 20", "11 + 6 * 16 + 20"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Check() = %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Index != i+1 || r.Target != w.target || len(r.Results) != 2 {
+			t.Fatalf("result %d = %+v, want index %d for target %d with 2 sets", i, r, i+1, w.target)
+		}
+		for j, expression := range []string{w.part1, w.part2} {
+			got := r.Results[j]
+			if got.Set != sets[j].Name || got.Solvable != (expression != "") || got.Expression != expression {
+				t.Errorf("%d with %s = %+v, want expression %q", w.target, sets[j].Name, got, expression)
+			}
+		}
+	}
+}
+
+// TestCheckOrder solves more equations than workers and checks the results
+// come back in input order however the workers finish.
+func TestCheckOrder(t *testing.T) {
+	var input strings.Builder
+	for i := range 300 {
+		// The numbers add up to the target when i%7 and i%2 match.
+		target := 2*i + i%2
+		fmt.Fprintf(&input, "%d: %d %d %d\n", target, i, i, i%7)
+	}
+	sets := []OperatorSet{{Name: "add", Operators: []Operator{Add}}}
+	for _, workers := range []int{0, 1, 3, 16} {
+		results, err := Check(input.String(), CheckOptions{Sets: sets, Workers: workers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bigResults, err := CheckBig(input.String(), CheckOptions{Sets: sets, Workers: workers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 300 || len(bigResults) != 300 {
+			t.Fatalf("%d workers: Check() = %d and %d results, want 300", workers, len(results), len(bigResults))
+		}
+		for i := range 300 {
+			target := 2*i + i%2
+			wantSolvable := i%7 == i%2
+			r, b := results[i], bigResults[i]
+			if r.Index != i+1 || r.Target != target || r.Results[0].Solvable != wantSolvable {
+				t.Fatalf("%d workers: result %d = %+v, want index %d, target %d, solvable %v", workers, i, r, i+1, target, wantSolvable)
+			}
+			if b.Index != i+1 || b.Target.Int64() != int64(target) || b.Results[0].Solvable != wantSolvable {
+				t.Fatalf("%d workers: big result %d = %+v, want index %d, target %d, solvable %v", workers, i, b, i+1, target, wantSolvable)
+			}
+		}
+	}
+}
+
+// reportResults are results with fixed timings, so their output is stable.
+func reportResults[T number](targets ...T) []EquationResult[T] {
+	results := make([]EquationResult[T], len(targets))
+	for i, target := range targets {
+		partOne := SetResult{Set: "part1", ElapsedMS: 0.25}
+		if i == 0 {
+			partOne.Solvable, partOne.Expression = true, "10 * 19"
+		}
+		results[i] = EquationResult[T]{
+			Index:  i + 1,
+			Target: target,
+			Results: []SetResult{
+				partOne,
+				{Set: "part2", Solvable: true, Expression: "15 || 6, \"quoted\"", ElapsedMS: 1.5},
+			},
+		}
+	}
+	return results
+}
+
+func TestWriteCSV(t *testing.T) {
+	sets := []OperatorSet{{Name: "part1"}, {Name: "part2"}}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sets, reportResults(190, 156)); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"index,target,part1_solvable,part1_expression,part1_elapsed_ms,part2_solvable,part2_expression,part2_elapsed_ms\n" +
+		"1,190,true,10 * 19,0.250,true,\"15 || 6, \"\"quoted\"\"\",1.500\n" +
+		"2,156,false,,0.250,true,\"15 || 6, \"\"quoted\"\"\",1.500\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	var bigBuf bytes.Buffer
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	if err := WriteCSV(&bigBuf, sets, reportResults(huge)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(strings.Split(bigBuf.String(), "\n")[1], "1,123456789012345678901234567890,true,") {
+		t.Errorf("WriteCSV() with a big target =\n%s", bigBuf.String())
+	}
+}
+
+func TestEquationResultJSON(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	tests := []struct {
+		name    string
+		results any
+		want    string
+	}{
+		{
+			name:    "int",
+			results: reportResults(190),
+			want: `[{"index":1,"target":190,"results":[` +
+				`{"set":"part1","solvable":true,"expression":"10 * 19","elapsed_ms":0.25},` +
+				`{"set":"part2","solvable":true,"expression":"15 || 6, \"quoted\"","elapsed_ms":1.5}]}]`,
+		},
+		{
+			// Unsolvable results leave the expression out, and big targets
+			// are written as plain numbers.
+			name:    "big",
+			results: reportResults(big.NewInt(1), huge)[1:],
+			want: `[{"index":2,"target":123456789012345678901234567890,"results":[` +
+				`{"set":"part1","solvable":false,"elapsed_ms":0.25},` +
+				`{"set":"part2","solvable":true,"expression":"15 || 6, \"quoted\"","elapsed_ms":1.5}]}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.results)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
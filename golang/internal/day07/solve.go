@@ -0,0 +1,262 @@
+package day07
+
+import (
+	"cmp"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+// number is what equations can be solved over: ints for speed, or big.Ints
+// for targets too large for an int.
+type number interface {
+	int | *big.Int
+}
+
+// arithmetic is what the solver needs to work with one kind of number.
+type arithmetic[T number] struct {
+	apply        func(op Operator, lhs T, rhs T) (T, bool)
+	inverse      func(op Operator, result T, rhs T) (T, bool)
+	inverseRange func(r RangeInverter, lo T, hi T, rhs T) (T, T, bool)
+	absorbs      func(a Absorber, rhs T) (T, bool)
+	compare      func(a T, b T) int
+	increment    func(a T) T
+	negative     func(a T) bool
+	// narrower reports whether hi - lo is less than width, for lo <= hi.
+	narrower func(lo T, hi T, width int) bool
+}
+
+var intArithmetic = arithmetic[int]{
+	apply:        Operator.Apply,
+	inverse:      Operator.Inverse,
+	inverseRange: RangeInverter.InverseRange,
+	absorbs:      Absorber.Absorbs,
+	compare:      cmp.Compare[int],
+	increment:    func(a int) int { return a + 1 },
+	negative:     func(a int) bool { return a < 0 },
+	narrower: func(lo int, hi int, width int) bool {
+		span, ok := checked.Sub(hi, lo)
+		return ok && span < width
+	},
+}
+
+var bigArithmetic = arithmetic[*big.Int]{
+	apply:        Operator.ApplyBig,
+	inverse:      Operator.InverseBig,
+	inverseRange: RangeInverter.InverseRangeBig,
+	absorbs:      Absorber.AbsorbsBig,
+	compare:      (*big.Int).Cmp,
+	increment:    func(a *big.Int) *big.Int { return new(big.Int).Add(a, big.NewInt(1)) },
+	negative:     func(a *big.Int) bool { return a.Sign() < 0 },
+	narrower: func(lo *big.Int, hi *big.Int, width int) bool {
+		span := new(big.Int).Sub(hi, lo)
+		return span.Cmp(big.NewInt(int64(width))) < 0
+	},
+}
+
+// maxInverseWidth is the widest range of targets an operator that isn't a
+// RangeInverter is undone for one target at a time. Past that the solver
+// searches forwards from the first number instead, which takes at worst one
+// try per choice of the other operators rather than one per target.
+const maxInverseWidth = 64
+
+// Solution is an equation along with the operators that make its numbers
+// evaluate to its target, strictly left to right.
+type Solution[T number] struct {
+	Target  T
+	Numbers []T
+	// Operators holds the symbol of the operator between each pair of
+	// numbers, or nil if no operators work.
+	Operators []string
+}
+
+// Solvable reports whether any operators make the equation true.
+func (s Solution[T]) Solvable() bool {
+	return s.Operators != nil
+}
+
+// Expression writes the numbers and operators out, such as "81 + 40 * 27",
+// or returns "" if the equation can't be solved.
+func (s Solution[T]) Expression() string {
+	if !s.Solvable() {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprint(&sb, s.Numbers[0])
+	for i, op := range s.Operators {
+		fmt.Fprintf(&sb, " %s %v", op, s.Numbers[i+1])
+	}
+	return sb.String()
+}
+
+// Solve finds operators from ops for every equation in input.
+func Solve(input string, ops []Operator) ([]Solution[int], error) {
+	equations, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return solveAll(equations, ops, intArithmetic), nil
+}
+
+// SolveBig is Solve for equations with numbers that don't fit in an int. Every
+// value is worked out exactly, so nothing can overflow, but it is a good deal
+// slower.
+func SolveBig(input string, ops []Operator) ([]Solution[*big.Int], error) {
+	equations, err := parseBigInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return solveAll(equations, ops, bigArithmetic), nil
+}
+
+func solveAll[T number](equations []equation[T], ops []Operator, arith arithmetic[T]) []Solution[T] {
+	solutions := make([]Solution[T], len(equations))
+	forEachEquation(len(equations), 0, func(i int) {
+		solutions[i], _ = equations[i].solve(ops, arith)
+	})
+	return solutions
+}
+
+// solve looks for operators from ops that make the equation true. Rather than
+// build every value the numbers can make going forwards, it works back from
+// the target, undoing each operator on the last number in turn. An operator
+// is only tried if it can be undone, which prunes nearly every branch: a
+// target that doesn't divide by the last number can't come from a
+// multiplication, and one that doesn't end in its digits can't come from a
+// concatenation. Undoing truncating division gives a whole range of values
+// rather than one, so the search carries a range of targets back, undoing
+// each operator for all of it at once. With ints, any operators found are
+// valid without overflow, as every value along the way was in a range of
+// targets that fit in an int.
+func (eq *equation[T]) solve(ops []Operator, arith arithmetic[T]) (Solution[T], bool) {
+	nonNegative := true
+	for _, num := range eq.candidateNums {
+		if arith.negative(num) {
+			nonNegative = false
+		}
+	}
+	for _, op := range ops {
+		if _, ok := op.(nonNegativeOperator); !ok {
+			nonNegative = false
+		}
+	}
+
+	s := backwardsSearch[T]{
+		arith:       arith,
+		nums:        eq.candidateNums,
+		ops:         ops,
+		chosen:      make([]string, len(eq.candidateNums)-1),
+		nonNegative: nonNegative,
+	}
+	solution := Solution[T]{Target: eq.target, Numbers: eq.candidateNums}
+	if !s.solve(eq.target, eq.target, len(eq.candidateNums)) {
+		return solution, false
+	}
+	solution.Operators = s.chosen
+	return solution, true
+}
+
+type backwardsSearch[T number] struct {
+	arith arithmetic[T]
+	nums  []T
+	ops   []Operator
+	// chosen[i] is the operator between nums[i] and nums[i+1], filled in as
+	// the search works back through them.
+	chosen []string
+	// nonNegative is set when no number is negative and no operator can make
+	// a negative value from ones that aren't, so a negative target can be
+	// given up on.
+	nonNegative bool
+}
+
+// solve reports whether the first n numbers can make any target from lo to
+// hi inclusive, filling in their operators if so.
+func (s *backwardsSearch[T]) solve(lo T, hi T, n int) bool {
+	if s.nonNegative && s.arith.negative(hi) {
+		return false
+	}
+	last := s.nums[n-1]
+	if n == 1 {
+		return s.within(last, lo, hi)
+	}
+
+	for _, op := range s.ops {
+		if absorber, ok := op.(Absorber); ok {
+			if result, absorbs := s.arith.absorbs(absorber, last); absorbs {
+				if s.within(result, lo, hi) && s.anyValue(1, s.nums[0], n-1) {
+					s.chosen[n-2] = op.Symbol()
+					return true
+				}
+				continue
+			}
+		}
+		if s.undo(op, lo, hi, n) {
+			s.chosen[n-2] = op.Symbol()
+			return true
+		}
+	}
+	return false
+}
+
+// undo reports whether the first n-1 numbers can make any lhs for which op
+// with the nth number gives a target from lo to hi, filling in their
+// operators if so. An operator that isn't a RangeInverter is undone for
+// each target in turn if there are at most maxInverseWidth of them, and
+// otherwise tried going forwards.
+func (s *backwardsSearch[T]) undo(op Operator, lo T, hi T, n int) bool {
+	last := s.nums[n-1]
+	if inverter, ok := op.(RangeInverter); ok {
+		restLo, restHi, ok := s.arith.inverseRange(inverter, lo, hi, last)
+		return ok && s.solve(restLo, restHi, n-1)
+	}
+	if !s.arith.narrower(lo, hi, maxInverseWidth) {
+		return s.forwards(op, 1, s.nums[0], lo, hi, n)
+	}
+	for target := lo; ; target = s.arith.increment(target) {
+		if rest, ok := s.arith.inverse(op, target, last); ok && s.solve(rest, rest, n-1) {
+			return true
+		}
+		if s.arith.compare(target, hi) == 0 {
+			return false
+		}
+	}
+}
+
+// forwards fills in operators that make the first n numbers evaluate to a
+// target from lo to hi with op as the last operator, given the first i of
+// them make value, trying every choice of the others going forwards.
+func (s *backwardsSearch[T]) forwards(op Operator, i int, value T, lo T, hi T, n int) bool {
+	if i == n-1 {
+		result, ok := s.arith.apply(op, value, s.nums[i])
+		return ok && s.within(result, lo, hi)
+	}
+	for _, next := range s.ops {
+		if v, ok := s.arith.apply(next, value, s.nums[i]); ok && s.forwards(op, i+1, v, lo, hi, n) {
+			s.chosen[i-1] = next.Symbol()
+			return true
+		}
+	}
+	return false
+}
+
+func (s *backwardsSearch[T]) within(value T, lo T, hi T) bool {
+	return s.arith.compare(lo, value) <= 0 && s.arith.compare(value, hi) <= 0
+}
+
+// anyValue fills in operators that make the first n numbers evaluate to
+// anything at all, given the first i of them make value, and reports false
+// if every choice fails, as on overflow.
+func (s *backwardsSearch[T]) anyValue(i int, value T, n int) bool {
+	if i == n {
+		return true
+	}
+	for _, op := range s.ops {
+		if next, ok := s.arith.apply(op, value, s.nums[i]); ok && s.anyValue(i+1, next, n) {
+			s.chosen[i-1] = op.Symbol()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,310 @@
+package day07
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// evaluate applies the operators with the given symbols between nums left to
+// right, reporting false if any step has no int result.
+func evaluate(nums []int, symbols []string, ops []Operator) (int, bool) {
+	value := nums[0]
+	for i, symbol := range symbols {
+		j := slices.IndexFunc(ops, func(op Operator) bool { return op.Symbol() == symbol })
+		if j < 0 {
+			return 0, false
+		}
+		var ok bool
+		if value, ok = ops[j].Apply(value, nums[i+1]); !ok {
+			return 0, false
+		}
+	}
+	return value, true
+}
+
+// solvableBruteForce tries every choice of operators going forwards.
+func solvableBruteForce(target int, nums []int, ops []Operator) bool {
+	var try func(i int, value int) bool
+	try = func(i int, value int) bool {
+		if i == len(nums) {
+			return value == target
+		}
+		for _, op := range ops {
+			if next, ok := op.Apply(value, nums[i]); ok && try(i+1, next) {
+				return true
+			}
+		}
+		return false
+	}
+	return try(1, nums[0])
+}
+
+func bigEquation(eq equation[int]) equation[*big.Int] {
+	nums := make([]*big.Int, len(eq.candidateNums))
+	for i, num := range eq.candidateNums {
+		nums[i] = big.NewInt(int64(num))
+	}
+	return equation[*big.Int]{target: big.NewInt(int64(eq.target)), candidateNums: nums}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		ops   []Operator
+		want  []string
+	}{
+		// 81 + 40 * 27 works too, but + is tried first on the last number.
+		{name: "two ways", input: "3267: 81 40 27", ops: PartOneOperators, want: []string{"*", "+"}},
+		{name: "concat", input: "156: 15 6", ops: PartTwoOperators, want: []string{"||"}},
+		{name: "no operators work", input: "83: 17 5", ops: PartTwoOperators, want: nil},
+		{name: "single number", input: "7: 7", ops: PartOneOperators, want: []string{}},
+		{name: "times zero", input: "0: 5 3 0", ops: PartOneOperators, want: []string{"+", "*"}},
+		{name: "negative", input: "-12: 3 15", ops: []Operator{Add, Sub}, want: []string{"-"}},
+		{name: "truncating division", input: "7: 15 2", ops: []Operator{Div}, want: []string{"/"}},
+		{name: "divide then add", input: "10: 23 3 3", ops: []Operator{Add, Div}, want: []string{"/", "+"}},
+		{name: "negative division", input: "-3: -7 2", ops: []Operator{Div}, want: []string{"/"}},
+		{name: "divide to zero", input: "0: 3 4 5", ops: []Operator{Add, Div}, want: []string{"/", "/"}},
+		{name: "binary concat", input: "71: 3 1 1", ops: []Operator{Concat, mustConcatBase(t, 2)}, want: []string{"||2", "||"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			solutions, err := Solve(tc.input, tc.ops)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(solutions[0].Operators, tc.want) || (solutions[0].Operators == nil) != (tc.want == nil) {
+				t.Errorf("Operators = %q, want %q", solutions[0].Operators, tc.want)
+			}
+		})
+	}
+}
+
+func mustConcatBase(t *testing.T, base int) Operator {
+	t.Helper()
+	op, err := ConcatBase(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return op
+}
+
+func TestSolveBruteForce(t *testing.T) {
+	opSets := [][]Operator{
+		PartOneOperators,
+		PartTwoOperators,
+		{Add, Sub, Mul},
+		{Sub, Concat, mustConcatBase(t, 2)},
+		{Add, Mul, Div},
+		{Sub, Div, Concat},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for range 2000 {
+		ops := opSets[rng.Intn(len(opSets))]
+		nums := make([]int, 1+rng.Intn(5))
+		for i := range nums {
+			nums[i] = rng.Intn(24) - 3
+		}
+		// Pick a target the numbers can make most of the time, so that
+		// solvable equations are well covered.
+		target := rng.Intn(200) - 20
+		if rng.Intn(4) > 0 {
+			symbols := make([]string, len(nums)-1)
+			for i := range symbols {
+				symbols[i] = ops[rng.Intn(len(ops))].Symbol()
+			}
+			if value, ok := evaluate(nums, symbols, ops); ok {
+				target = value
+			}
+		}
+
+		eq := equation[int]{target: target, candidateNums: nums}
+		want := solvableBruteForce(target, nums, ops)
+		solution, got := eq.solve(ops, intArithmetic)
+		if got != want {
+			t.Fatalf("%d: %v with %d operator(s): solvable = %t, want %t", target, nums, len(ops), got, want)
+		}
+		if got {
+			if value, ok := evaluate(nums, solution.Operators, ops); !ok || value != target {
+				t.Fatalf("%d: %v: %s doesn't make the target", target, nums, solution.Expression())
+			}
+		}
+		bigEq := bigEquation(eq)
+		if _, gotBig := bigEq.solve(ops, bigArithmetic); gotBig != want {
+			t.Fatalf("%d: %v with %d operator(s): big solvable = %t, want %t", target, nums, len(ops), gotBig, want)
+		}
+	}
+}
+
+// rangeInverters lists the built-in operators with a name for each.
+func rangeInverters(t *testing.T) map[string]RangeInverter {
+	inverters := make(map[string]RangeInverter)
+	for _, op := range []Operator{Add, Sub, Mul, Div, Concat, mustConcatBase(t, 2)} {
+		inverters[op.Symbol()] = op.(RangeInverter)
+	}
+	return inverters
+}
+
+func TestInverseRange(t *testing.T) {
+	for symbol, inverter := range rangeInverters(t) {
+		op := inverter.(Operator)
+		for rhs := -6; rhs <= 6; rhs++ {
+			for lo := -15; lo <= 15; lo++ {
+				for hi := lo; hi <= lo+4; hi++ {
+					var want []int
+					for lhs := -300; lhs <= 300; lhs++ {
+						if got, ok := op.Apply(lhs, rhs); ok && got >= lo && got <= hi {
+							want = append(want, lhs)
+						}
+					}
+					if symbol == "*" && rhs == 0 {
+						// Absorbs covers this.
+						want = nil
+					}
+					lhsLo, lhsHi, ok := inverter.InverseRange(lo, hi, rhs)
+					if len(want) == 0 {
+						if ok {
+							t.Errorf("%s: InverseRange(%d, %d, %d) = %d, %d, want none", symbol, lo, hi, rhs, lhsLo, lhsHi)
+						}
+						continue
+					}
+					if !ok || lhsLo != want[0] || lhsHi != want[len(want)-1] || len(want) != lhsHi-lhsLo+1 {
+						t.Errorf("%s: InverseRange(%d, %d, %d) = %d, %d, %t, want %v", symbol, lo, hi, rhs, lhsLo, lhsHi, ok, want)
+						continue
+					}
+					bigLo, bigHi, ok := inverter.InverseRangeBig(big.NewInt(int64(lo)), big.NewInt(int64(hi)), big.NewInt(int64(rhs)))
+					if !ok || bigLo.Int64() != int64(lhsLo) || bigHi.Int64() != int64(lhsHi) {
+						t.Errorf("%s: InverseRangeBig(%d, %d, %d) = %v, %v, %t, want %d, %d", symbol, lo, hi, rhs, bigLo, bigHi, ok, lhsLo, lhsHi)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestInverseRangeLimits checks the int ranges near the ends of an int
+// against the big ones cut down to fit.
+func TestInverseRangeLimits(t *testing.T) {
+	edges := []int{math.MinInt, math.MinInt + 1, math.MinInt / 2, -1000, -2, -1, 0, 1, 2, 1000, math.MaxInt / 2, math.MaxInt - 1, math.MaxInt}
+	minInt, maxInt := big.NewInt(math.MinInt), big.NewInt(math.MaxInt)
+	for symbol, inverter := range rangeInverters(t) {
+		for _, rhs := range edges {
+			if symbol == "*" && rhs == 0 {
+				continue
+			}
+			if c, ok := inverter.(concatOperator); ok {
+				if _, ok := c.shift(rhs); !ok {
+					// Apply never works with an rhs this long.
+					continue
+				}
+			}
+			for _, lo := range edges {
+				for _, hi := range edges {
+					if hi < lo {
+						continue
+					}
+					lhsLo, lhsHi, ok := inverter.InverseRange(lo, hi, rhs)
+					bigLo, bigHi, bigOK := inverter.InverseRangeBig(big.NewInt(int64(lo)), big.NewInt(int64(hi)), big.NewInt(int64(rhs)))
+					if bigOK {
+						if bigLo.Cmp(minInt) < 0 {
+							bigLo = minInt
+						}
+						if bigHi.Cmp(maxInt) > 0 {
+							bigHi = maxInt
+						}
+						bigOK = bigLo.Cmp(bigHi) <= 0
+					}
+					if symbol == "/" && rhs == -1 && bigOK && bigLo.Cmp(minInt) == 0 {
+						// MinInt / -1 overflows, so MinInt isn't a valid lhs.
+						bigLo = big.NewInt(math.MinInt + 1)
+						bigOK = bigLo.Cmp(bigHi) <= 0
+					}
+					if ok != bigOK || (ok && (int64(lhsLo) != bigLo.Int64() || int64(lhsHi) != bigHi.Int64())) {
+						t.Errorf("%s: InverseRange(%d, %d, %d) = %d, %d, %t, want %v, %v, %t", symbol, lo, hi, rhs, lhsLo, lhsHi, ok, bigLo, bigHi, bigOK)
+					}
+				}
+			}
+		}
+	}
+}
+
+// plainOperator hides every interface of an operator but Operator, as one
+// written outside the package might have.
+type plainOperator struct {
+	Operator
+}
+
+func TestSolveWithoutRangeInverter(t *testing.T) {
+	plainAdd, plainSub := plainOperator{Add}, plainOperator{Sub}
+	tests := []struct {
+		name  string
+		input string
+		ops   []Operator
+		want  []string
+	}{
+		{name: "narrow range", input: "5: 18 3 4", ops: []Operator{plainAdd, Div}, want: []string{"+", "/"}},
+		// Undoing the division leaves about two quadrillion targets for the
+		// addition, too many to undo one at a time.
+		{name: "wide range", input: "0: 3 4 1000000000000000", ops: []Operator{plainAdd, Div}, want: []string{"+", "/"}},
+		{name: "wide range unsolvable", input: "5: 3 4 1000000000000000", ops: []Operator{plainAdd, Div}, want: nil},
+		{name: "wide range further back", input: "0: 5 6 7 1000000000000000", ops: []Operator{plainAdd, Div}, want: []string{"+", "+", "/"}},
+		{name: "wide range subtracting", input: "0: 5 6 1000000000000000", ops: []Operator{plainSub, Div}, want: []string{"-", "/"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			solutions, err := Solve(tc.input, tc.ops)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(solutions[0].Operators, tc.want) || (solutions[0].Operators == nil) != (tc.want == nil) {
+				t.Errorf("Operators = %q, want %q", solutions[0].Operators, tc.want)
+			}
+			bigSolutions, err := SolveBig(tc.input, tc.ops)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(bigSolutions[0].Operators, tc.want) || (bigSolutions[0].Operators == nil) != (tc.want == nil) {
+				t.Errorf("big Operators = %q, want %q", bigSolutions[0].Operators, tc.want)
+			}
+		})
+	}
+}
+
+// TestSolveWithoutRangeInverterBruteForce divides by numbers big enough that
+// the operators that aren't RangeInverters are undone both one target at a
+// time and by searching forwards.
+func TestSolveWithoutRangeInverterBruteForce(t *testing.T) {
+	opSets := [][]Operator{
+		{plainOperator{Add}, Div},
+		{plainOperator{Sub}, plainOperator{Concat}, Div},
+		{Mul, plainOperator{Add}, Div},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for range 2000 {
+		ops := opSets[rng.Intn(len(opSets))]
+		nums := make([]int, 1+rng.Intn(4))
+		for i := range nums {
+			nums[i] = rng.Intn(300) - 20
+		}
+		target := rng.Intn(40) - 10
+
+		eq := equation[int]{target: target, candidateNums: nums}
+		want := solvableBruteForce(target, nums, ops)
+		solution, got := eq.solve(ops, intArithmetic)
+		if got != want {
+			t.Fatalf("%d: %v with %d operator(s): solvable = %t, want %t", target, nums, len(ops), got, want)
+		}
+		if got {
+			if value, ok := evaluate(nums, solution.Operators, ops); !ok || value != target {
+				t.Fatalf("%d: %v: %s doesn't make the target", target, nums, solution.Expression())
+			}
+		}
+		bigEq := bigEquation(eq)
+		if _, gotBig := bigEq.solve(ops, bigArithmetic); gotBig != want {
+			t.Fatalf("%d: %v with %d operator(s): big solvable = %t, want %t", target, nums, len(ops), gotBig, want)
+		}
+	}
+}
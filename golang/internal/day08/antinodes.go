@@ -0,0 +1,191 @@
+package day08
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// StepMode is how far apart the points taken along the line through a pair
+// of antennas are.
+type StepMode string
+
+const (
+	// StepRaw steps by the full difference between the antennas, as the
+	// puzzle does, so with antennas (2,4) apart only every other grid point
+	// on the line is taken.
+	StepRaw StepMode = "raw"
+	// StepReduced divides the difference by the gcd of its parts, so that
+	// every grid point on the line is taken.
+	StepReduced StepMode = "reduced"
+)
+
+// Rule decides which points on the line through a pair of antennas are
+// antinodes, by their distance from each antenna.
+type Rule struct {
+	// Ratios lists how many times further from one antenna than the other a
+	// point must be, so part one's rule is {2}. With no ratios every point on
+	// the line is an antinode, antennas included, as in part two.
+	Ratios []int
+}
+
+func (r Rule) accepts(distanceA int, distanceB int) bool {
+	if len(r.Ratios) == 0 {
+		return true
+	}
+	for _, ratio := range r.Ratios {
+		if distanceA == ratio*distanceB || distanceB == ratio*distanceA {
+			// Both distances are zero only for an antenna paired with itself,
+			// which never happens.
+			return true
+		}
+	}
+	return false
+}
+
+// Options configures Antinodes.
+type Options struct {
+	Step StepMode
+	Rule Rule
+}
+
+// The puzzle's rules for each part.
+var (
+	PartOneOptions = Options{Step: StepRaw, Rule: Rule{Ratios: []int{2}}}
+	PartTwoOptions = Options{Step: StepRaw}
+)
+
+func (opts Options) validate() error {
+	switch opts.Step {
+	case StepRaw, StepReduced:
+	default:
+		return fmt.Errorf("unknown step %q, expected %s or %s", opts.Step, StepRaw, StepReduced)
+	}
+	for _, ratio := range opts.Rule.Ratios {
+		if ratio < 1 {
+			return fmt.Errorf("distance ratios must be at least 1, got %d", ratio)
+		}
+	}
+	return nil
+}
+
+// Cell is a cell of the map.
+type Cell struct {
+	Row int
+	Col int
+}
+
+// FrequencyAntinodes holds the antinodes made by the antennas of one
+// frequency, in reading order.
+type FrequencyAntinodes struct {
+	Frequency string
+	Antennas  []Cell
+	Antinodes []Cell
+}
+
+// Antinodes finds the antinodes of every frequency on the map, sorted by
+// frequency. For each pair of antennas of a frequency it walks every point
+// on the line through them that is a whole number of steps from the first,
+// keeping those the rule accepts.
+func Antinodes(input string, opts Options) ([]FrequencyAntinodes, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	gm := createGameMap(parseInput(input))
+
+	frequencies := make([]string, 0, len(gm.freqToAntennas))
+	for freq := range gm.freqToAntennas {
+		frequencies = append(frequencies, freq)
+	}
+	slices.Sort(frequencies)
+
+	groups := make([]FrequencyAntinodes, len(frequencies))
+	for i, freq := range frequencies {
+		antennas := gm.freqToAntennas[freq]
+		antinodes := make(map[coordinate]struct{})
+		for a := range antennas {
+			for b := a + 1; b < len(antennas); b++ {
+				for _, antinode := range gm.lineAntinodes(antennas[a], antennas[b], opts) {
+					antinodes[antinode] = struct{}{}
+				}
+			}
+		}
+		groups[i] = FrequencyAntinodes{
+			Frequency: freq,
+			Antennas:  sortedCells(antennas),
+			Antinodes: sortedCells(mapKeys(antinodes)),
+		}
+	}
+	return groups, nil
+}
+
+// lineAntinodes walks the line through a and b both ways from a until it
+// leaves the map, keeping the points the rule accepts. Distances are counted
+// in steps, of which b is n from a.
+func (gm *gameMap) lineAntinodes(a coordinate, b coordinate, opts Options) []coordinate {
+	step := coordinate{b.row - a.row, b.col - a.col}
+	n := 1
+	if opts.Step == StepReduced {
+		n = gcd(abs(step.row), abs(step.col))
+		step = coordinate{step.row / n, step.col / n}
+	}
+	backwards := coordinate{-step.row, -step.col}
+
+	antinodes := make([]coordinate, 0)
+	for k, point := 0, a; !gm.isOffMap(point); k, point = k+1, point.sum(step) {
+		if opts.Rule.accepts(k, abs(k-n)) {
+			antinodes = append(antinodes, point)
+		}
+	}
+	for k, point := -1, a.sum(backwards); !gm.isOffMap(point); k, point = k-1, point.sum(backwards) {
+		if opts.Rule.accepts(-k, n-k) {
+			antinodes = append(antinodes, point)
+		}
+	}
+	return antinodes
+}
+
+// Distinct returns every cell that is an antinode for any frequency, in
+// reading order.
+func Distinct(groups []FrequencyAntinodes) []Cell {
+	seen := make(map[coordinate]struct{})
+	for _, group := range groups {
+		for _, cell := range group.Antinodes {
+			seen[coordinate{cell.Row, cell.Col}] = struct{}{}
+		}
+	}
+	return sortedCells(mapKeys(seen))
+}
+
+func mapKeys(set map[coordinate]struct{}) []coordinate {
+	keys := make([]coordinate, 0, len(set))
+	for coord := range set {
+		keys = append(keys, coord)
+	}
+	return keys
+}
+
+func sortedCells(coords []coordinate) []Cell {
+	cells := make([]Cell, len(coords))
+	for i, coord := range coords {
+		cells[i] = Cell{Row: coord.row, Col: coord.col}
+	}
+	slices.SortFunc(cells, func(a Cell, b Cell) int {
+		return cmp.Or(cmp.Compare(a.Row, b.Row), cmp.Compare(a.Col, b.Col))
+	})
+	return cells
+}
+
+func gcd(a int, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
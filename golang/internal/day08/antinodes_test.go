@@ -0,0 +1,180 @@
+package day08
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// antinodesBruteForce checks every cell of the map against every pair of
+// antennas, using squared straight line distances for the rule.
+func antinodesBruteForce(input string, opts Options) []Cell {
+	gm := createGameMap(parseInput(input))
+	found := make(map[coordinate]struct{})
+	for row := range gm.rawMap {
+		for col := range gm.rawMap[row] {
+			p := coordinate{row, col}
+			for _, antennas := range gm.freqToAntennas {
+				for i, a := range antennas {
+					for _, b := range antennas[i+1:] {
+						if onStep(p, a, b, opts.Step) && acceptsDistances(p, a, b, opts.Rule) {
+							found[p] = struct{}{}
+						}
+					}
+				}
+			}
+		}
+	}
+	return sortedCells(mapKeys(found))
+}
+
+// onStep reports whether p is on the line through a and b at a point the
+// step mode takes.
+func onStep(p coordinate, a coordinate, b coordinate, step StepMode) bool {
+	d := coordinate{b.row - a.row, b.col - a.col}
+	offset := coordinate{p.row - a.row, p.col - a.col}
+	if offset.row*d.col != offset.col*d.row {
+		return false
+	}
+	if step == StepReduced {
+		return true
+	}
+	// Raw steps only reach whole multiples of the difference.
+	if d.row != 0 {
+		return offset.row%d.row == 0
+	}
+	return offset.col%d.col == 0
+}
+
+func acceptsDistances(p coordinate, a coordinate, b coordinate, rule Rule) bool {
+	if len(rule.Ratios) == 0 {
+		return true
+	}
+	squaredA := (p.row-a.row)*(p.row-a.row) + (p.col-a.col)*(p.col-a.col)
+	squaredB := (p.row-b.row)*(p.row-b.row) + (p.col-b.col)*(p.col-b.col)
+	for _, ratio := range rule.Ratios {
+		if squaredA == ratio*ratio*squaredB || squaredB == ratio*ratio*squaredA {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAntinodes(t *testing.T) {
+	// Two antennas (2,2) apart on a 6x6 map.
+	const diagonal = "......\n.a....\n......\n...a..\n......\n......\n"
+	tests := []struct {
+		name  string
+		input string
+		opts  Options
+		want  []Cell
+	}{
+		{
+			name:  "part one",
+			input: diagonal,
+			opts:  PartOneOptions,
+			want:  []Cell{{5, 5}},
+		},
+		{
+			name:  "part two",
+			input: diagonal,
+			opts:  PartTwoOptions,
+			want:  []Cell{{1, 1}, {3, 3}, {5, 5}},
+		},
+		{
+			name:  "reduced step takes every point on the line",
+			input: diagonal,
+			opts:  Options{Step: StepReduced},
+			want:  []Cell{{0, 0}, {1, 1}, {2, 2}, {3, 3}, {4, 4}, {5, 5}},
+		},
+		{
+			name:  "ratio one is the midpoint",
+			input: diagonal,
+			opts:  Options{Step: StepReduced, Rule: Rule{Ratios: []int{1}}},
+			want:  []Cell{{2, 2}},
+		},
+		{
+			name:  "raw steps miss the midpoint",
+			input: diagonal,
+			opts:  Options{Step: StepRaw, Rule: Rule{Ratios: []int{1}}},
+			want:  []Cell{},
+		},
+		{
+			name:  "lone antenna",
+			input: "...\n.a.\n...\n",
+			opts:  PartTwoOptions,
+			want:  []Cell{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			groups, err := Antinodes(tc.input, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Distinct(groups); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Distinct() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAntinodesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{name: "unknown step", opts: Options{Step: "diagonal"}, want: `unknown step "diagonal"`},
+		{name: "zero ratio", opts: Options{Step: StepRaw, Rule: Rule{Ratios: []int{2, 0}}}, want: "distance ratios must be at least 1, got 0"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Antinodes("a.a\n", tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Antinodes() error = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestAntinodesBruteForce(t *testing.T) {
+	optionSets := []Options{
+		PartOneOptions,
+		PartTwoOptions,
+		{Step: StepReduced},
+		{Step: StepReduced, Rule: Rule{Ratios: []int{1, 3}}},
+		{Step: StepRaw, Rule: Rule{Ratios: []int{1, 2, 3}}},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for range 300 {
+		rows, cols := 1+rng.Intn(10), 1+rng.Intn(10)
+		var b strings.Builder
+		for range rows {
+			for range cols {
+				switch rng.Intn(8) {
+				case 0:
+					b.WriteByte('a')
+				case 1:
+					b.WriteByte('B')
+				default:
+					b.WriteByte('.')
+				}
+			}
+			b.WriteByte('\n')
+		}
+		input := b.String()
+
+		for _, opts := range optionSets {
+			groups, err := Antinodes(input, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, want := Distinct(groups), antinodesBruteForce(input, opts)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%+v: Distinct() = %v, want %v, for\n%s", opts, got, want, input)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package day08 solves day 8, Resonant Collinearity.
+package day08
+
+import (
+	"bufio"
+	"github.com/samber/lo"
+	"strconv"
+	"strings"
+)
+
+type coordinate struct {
+	row, col int
+}
+
+func (c *coordinate) sum(other coordinate) coordinate {
+	return coordinate{c.row + other.row, c.col + other.col}
+}
+
+type gameMap struct {
+	rawMap [][]string
+
+	freqToAntennas map[string][]coordinate
+}
+
+func (gm *gameMap) isOffMap(coord coordinate) bool {
+	if coord.row < 0 || coord.col < 0 {
+		return true
+	}
+	if coord.row >= len(gm.rawMap) || coord.col >= len(gm.rawMap[0]) {
+		return true
+	}
+	return false
+}
+
+func createGameMap(rawMap [][]string) gameMap {
+	freqToAntennas := make(map[string][]coordinate)
+	for row := range rawMap {
+		for col := range rawMap[row] {
+			if rawMap[row][col] != "." {
+				freq := rawMap[row][col]
+				if _, ok := freqToAntennas[freq]; !ok {
+					freqToAntennas[freq] = make([]coordinate, 0)
+				}
+				freqToAntennas[freq] = append(freqToAntennas[freq], coordinate{row, col})
+			}
+		}
+	}
+
+	return gameMap{
+		rawMap:         rawMap,
+		freqToAntennas: freqToAntennas,
+	}
+}
+
+func handleLine(line string) []string {
+	return lo.ChunkString(line, 1)
+}
+
+func parseInput(input string) [][]string {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	rawMap := make([][]string, 0)
+
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		rawMap = append(rawMap, handleLine(scanner.Text()))
+	}
+	return rawMap
+}
+
+type Solver struct{}
+
+func countAntinodes(input string, opts Options) (string, error) {
+	groups, err := Antinodes(input, opts)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(len(Distinct(groups))), nil
+}
+
+func (Solver) PartOne(input string) (string, error) {
+	return countAntinodes(input, PartOneOptions)
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	return countAntinodes(input, PartTwoOptions)
+}
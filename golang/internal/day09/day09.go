@@ -0,0 +1,232 @@
+// Package day09 solves day 9, Disk Fragmenter.
+package day09
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/samber/lo"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type file struct {
+	size int
+	id   int
+}
+
+func handleLine(line string) ([]file, []int, error) {
+	nums := lo.ChunkString(line, 1)
+	files := make([]file, 0)
+	freeSpace := make([]int, 0)
+	isFile := true
+	fileId := 0
+	for _, numStr := range nums {
+		num, err := strconv.Atoi(numStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isFile {
+			files = append(files, file{size: num, id: fileId})
+			fileId += 1
+		} else {
+			freeSpace = append(freeSpace, num)
+		}
+		isFile = !isFile
+	}
+
+	return files, freeSpace, nil
+}
+
+func getDiskLayout(files []file, space []int) ([]int, error) {
+	disk := make([]int, 0)
+	if len(files)-1 != len(space) {
+		return nil, errors.New("number of files should be one more than the number of spaces")
+	}
+	for i := range files {
+		for range files[i].size {
+			disk = append(disk, files[i].id)
+		}
+		if i == len(files)-1 {
+			// Avoid going out of bounds since we have 1 more file than space.
+			break
+		}
+		for range space[i] {
+			disk = append(disk, -1)
+		}
+	}
+
+	return disk, nil
+}
+
+func compactDiskPart1(disk []int) []int {
+	compactedDisk := make([]int, len(disk))
+	copy(compactedDisk, disk)
+	for slices.Contains(compactedDisk, -1) {
+		emptyIndex := slices.Index(compactedDisk, -1)
+		for j := len(compactedDisk) - 1; j > 0; j-- {
+			if compactedDisk[j] != -1 {
+				compactedDisk[emptyIndex] = compactedDisk[j]
+				compactedDisk = compactedDisk[:j]
+				break
+			}
+		}
+	}
+	return compactedDisk
+}
+
+type diskAddress struct {
+	index int
+	size  int
+}
+
+func findFreeSpaces(disk []int) []diskAddress {
+	freeSpaces := make([]diskAddress, 0)
+	inFreeSpace := false
+	var freeSpaceCount, index int
+	for i := range disk {
+		if disk[i] == -1 {
+			if !inFreeSpace {
+				inFreeSpace = true
+				index = i
+			}
+			freeSpaceCount += 1
+		} else if inFreeSpace {
+			// Close off the free space we were counting
+			freeSpaces = append(freeSpaces, diskAddress{index: index, size: freeSpaceCount})
+			freeSpaceCount = 0
+			inFreeSpace = false
+		}
+	}
+
+	return freeSpaces
+}
+
+func findFileNum(disk []int, fileNum int) (diskAddress, error) {
+	index := slices.Index(disk, fileNum)
+	if index < 0 {
+		return diskAddress{}, fmt.Errorf("file %d has no blocks on the disk", fileNum)
+	}
+	count := 0
+	for i := index; i < len(disk); i++ {
+		if disk[i] == fileNum {
+			count++
+		} else {
+			break
+		}
+	}
+
+	return diskAddress{index: index, size: count}, nil
+}
+
+func compactDiskPart2(disk []int) ([]int, error) {
+	compactedDisk := make([]int, len(disk))
+	copy(compactedDisk, disk)
+
+	var fileNum int
+	for j := len(compactedDisk) - 1; j > 0; j-- {
+		if compactedDisk[j] != -1 {
+			fileNum = compactedDisk[j]
+			break
+		}
+	}
+
+	for fileNum > 0 {
+		freeSpaceLocations := findFreeSpaces(compactedDisk)
+		fileLocation, err := findFileNum(compactedDisk, fileNum)
+		if err != nil {
+			return nil, err
+		}
+		for i := range freeSpaceLocations {
+			if freeSpaceLocations[i].index > fileLocation.index {
+				// Don't move files into later free space.
+				break
+			}
+			if freeSpaceLocations[i].size >= fileLocation.size {
+				for j := 0; j < fileLocation.size; j++ {
+					index := freeSpaceLocations[i].index + j
+					if compactedDisk[index] != -1 {
+						return nil, fmt.Errorf("logic error: moving file %d into used block %d", fileNum, index)
+					}
+					compactedDisk[index] = fileNum
+					index = fileLocation.index + j
+					if compactedDisk[index] != fileNum {
+						return nil, fmt.Errorf("logic error: block %d isn't part of file %d", index, fileNum)
+					}
+					compactedDisk[index] = -1
+				}
+				break
+			}
+		}
+		fileNum -= 1
+	}
+
+	return compactedDisk, nil
+}
+
+func printDisk(disk []int) {
+	for i := range disk {
+		if disk[i] > -1 {
+			print(disk[i])
+		} else {
+			print(".")
+		}
+	}
+	println()
+}
+
+func parseInput(input string) ([]int, error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+
+	var files []file
+	var freeSpace []int
+
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		var err error
+		files, freeSpace, err = handleLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return getDiskLayout(files, freeSpace)
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	disk, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	compactedDisk := compactDiskPart1(disk)
+	checksum := 0
+	for i := range compactedDisk {
+		checksum += compactedDisk[i] * i
+	}
+	return strconv.Itoa(checksum), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	disk, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	compactedDisk, err := compactDiskPart2(disk)
+	if err != nil {
+		return "", err
+	}
+	checksum := 0
+	for i := range compactedDisk {
+		if compactedDisk[i] > -1 {
+			checksum += compactedDisk[i] * i
+		}
+	}
+	return strconv.Itoa(checksum), nil
+}
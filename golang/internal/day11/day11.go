@@ -0,0 +1,207 @@
+// Package day11 solves day 11, Plutonian Pebbles.
+package day11
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+func handleLine(line string) ([]int, error) {
+	stones := make([]int, 0)
+	stoneNumStrings := strings.Fields(line)
+	for i := range stoneNumStrings {
+		stone, err := strconv.Atoi(stoneNumStrings[i])
+		if err != nil {
+			return nil, err
+		}
+		stones = append(stones, stone)
+	}
+
+	return stones, nil
+}
+
+func blinkStone(stone int) ([]int, error) {
+	if stone == 0 {
+		return []int{1}, nil
+	}
+	stoneStr := strconv.Itoa(stone)
+	if len(stoneStr)%2 == 0 {
+		// We can get away with just operating on the string because we have ascii chars, but in general this is wonky.
+		lhsStr := stoneStr[:len(stoneStr)/2]
+		// lhs should require dropping, but do it just in case.
+		for rune(lhsStr[0]) == '0' {
+			lhsStr = lhsStr[1:]
+		}
+		lhs, err := strconv.Atoi(lhsStr)
+		if err != nil {
+			return nil, err
+		}
+		rhsStr := stoneStr[len(stoneStr)/2:]
+		for len(rhsStr) > 1 && rune(rhsStr[0]) == '0' {
+			rhsStr = rhsStr[1:]
+		}
+		rhs, err := strconv.Atoi(rhsStr)
+		if err != nil {
+			return nil, err
+		}
+		return []int{lhs, rhs}, nil
+	}
+
+	multiplied, ok := checked.Mul(stone, 2024)
+	if !ok {
+		return nil, fmt.Errorf("%w: stone %d * 2024", checked.ErrOverflow, stone)
+	}
+	return []int{multiplied}, nil
+}
+
+func blinkToDepth(
+	stones []int,
+	searchDepth int,
+	stoneToNext map[int][]int,
+	stoneToMaxDepth map[int]int,
+) error {
+	frontier := stones
+	depthLeft := searchDepth
+	for depthLeft > 0 {
+		newFrontier := make([]int, 0)
+		for i := range frontier {
+			mapDepth, ok := stoneToMaxDepth[frontier[i]]
+			if !ok {
+				stoneToMaxDepth[frontier[1]] = depthLeft
+			} else if mapDepth > depthLeft {
+				// We've already seen this stone, skip it.
+				continue
+			}
+
+			_, ok = stoneToNext[frontier[i]]
+			if ok {
+				// We've already seen this stone.
+				continue
+			}
+
+			blinkedStones, err := blinkStone(frontier[i])
+			if err != nil {
+				return err
+			}
+			stoneToNext[frontier[i]] = blinkedStones
+			for _, blinkedStone := range blinkedStones {
+				newFrontier = append(newFrontier, blinkedStone)
+			}
+			newFrontier = lo.Uniq(newFrontier)
+		}
+		frontier = newFrontier
+		depthLeft -= 1
+	}
+	return nil
+}
+
+type stoneAndDepthLeft struct {
+	stone     int
+	depthLeft int
+}
+
+func countChildren(
+	stone int,
+	depthLeft int,
+	stoneToNext map[int][]int,
+	stoneAtDepthLeftToChildCount map[stoneAndDepthLeft]int,
+) (int, error) {
+	if depthLeft == 1 {
+		return len(stoneToNext[stone]), nil
+	}
+
+	// Check for cached count.
+	cachedCount, ok := stoneAtDepthLeftToChildCount[stoneAndDepthLeft{stone, depthLeft}]
+	if ok {
+		return cachedCount, nil
+	}
+
+	// No cached count, get calculating.
+	count := 0
+	for _, child := range stoneToNext[stone] {
+		childCount, err := countChildren(child, depthLeft-1, stoneToNext, stoneAtDepthLeftToChildCount)
+		if err != nil {
+			return 0, err
+		}
+		count, ok = checked.Add(count, childCount)
+		if !ok {
+			return 0, fmt.Errorf("%w: stone count after %d blinks", checked.ErrOverflow, depthLeft)
+		}
+	}
+	// Cache our count before returning.
+	stoneAtDepthLeftToChildCount[stoneAndDepthLeft{stone: stone, depthLeft: depthLeft}] = count
+	return count, nil
+}
+
+func parseInput(input string) ([]int, error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+
+	var stones []int
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		var err error
+		stones, err = handleLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stones, nil
+}
+
+func countStonesAfterBlinks(stones []int, blinks int) (int, error) {
+	stoneToNext := make(map[int][]int)
+	stoneToMaxDepth := make(map[int]int)
+
+	if err := blinkToDepth(stones, blinks, stoneToNext, stoneToMaxDepth); err != nil {
+		return 0, err
+	}
+
+	stoneAndDepthLeftToChildCount := make(map[stoneAndDepthLeft]int)
+	count := 0
+	for _, stone := range stones {
+		childCount, err := countChildren(stone, blinks, stoneToNext, stoneAndDepthLeftToChildCount)
+		if err != nil {
+			return 0, err
+		}
+		var ok bool
+		count, ok = checked.Add(count, childCount)
+		if !ok {
+			return 0, fmt.Errorf("%w: stone count after %d blinks", checked.ErrOverflow, blinks)
+		}
+	}
+	return count, nil
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	stones, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	count, err := countStonesAfterBlinks(stones, 25)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(count), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	stones, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	count, err := countStonesAfterBlinks(stones, 75)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(count), nil
+}
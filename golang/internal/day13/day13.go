@@ -0,0 +1,345 @@
+// Package day13 solves day 13, Claw Contraption.
+package day13
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+const (
+	buttonALine = iota
+	buttonBLine
+	prizeLine
+)
+
+const (
+	buttonACost = 3
+	buttonBCost = 1
+)
+
+// prizeOffset is added to every prize coordinate for part 2.
+const prizeOffset = 10_000_000_000_000
+
+var buttonARegex = regexp.MustCompile(`Button A: X\+(\d+), Y\+(\d+)`)
+var buttonBRegex = regexp.MustCompile(`Button B: X\+(\d+), Y\+(\d+)`)
+var prizeRegex = regexp.MustCompile(`Prize: X=(\d+), Y=(\d+)`)
+
+// Returns (x, y, lineType)
+func handleLine(line string) (int, int, int, error) {
+	matches := buttonARegex.FindStringSubmatch(line)
+	if matches != nil {
+		x, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return -1, -1, -1, err
+		}
+		y, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return -1, -1, -1, err
+		}
+		return x, y, buttonALine, nil
+	}
+	matches = buttonBRegex.FindStringSubmatch(line)
+	if matches != nil {
+		x, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return -1, -1, -1, err
+		}
+		y, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return -1, -1, -1, err
+		}
+		return x, y, buttonBLine, nil
+	}
+	matches = prizeRegex.FindStringSubmatch(line)
+	if matches != nil {
+		x, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return -1, -1, -1, err
+		}
+		y, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return -1, -1, -1, err
+		}
+		return x, y, prizeLine, nil
+	}
+	return -1, -1, -1, nil
+}
+
+type coordinate struct {
+	x int
+	y int
+}
+
+func (c *coordinate) add(other coordinate) coordinate {
+	return coordinate{c.x + other.x, c.y + other.y}
+}
+
+func (c *coordinate) sub(other coordinate) coordinate {
+	return coordinate{c.x - other.x, c.y - other.y}
+}
+
+func (c *coordinate) mul(x int) coordinate {
+	return coordinate{c.x * x, c.y * x}
+}
+
+type clawMachine struct {
+	buttonAMove   coordinate
+	buttonBMove   coordinate
+	prizeLocation coordinate
+}
+
+func (cm *clawMachine) subGame(c coordinate) clawMachine {
+	prizeLocation := cm.prizeLocation.sub(c)
+	return clawMachine{
+		buttonAMove:   cm.buttonAMove,
+		buttonBMove:   cm.buttonBMove,
+		prizeLocation: prizeLocation,
+	}
+}
+
+type clawMachineMoveChain struct {
+	aPressCount         int
+	bPressCount         int
+	currentClawPosition coordinate
+	cost                int
+}
+
+type presses struct {
+	aPresses int
+	bPresses int
+}
+
+func (cm *clawMachine) bruteForceSolutions(maxPresses int) []clawMachineMoveChain {
+	viableSolutions := make([]clawMachineMoveChain, 0)
+
+	// BFS.
+	remainingDepth := maxPresses - 1
+	frontier := []clawMachineMoveChain{
+		{aPressCount: 1, bPressCount: 0, currentClawPosition: cm.buttonAMove, cost: buttonACost},
+		{aPressCount: 0, bPressCount: 1, currentClawPosition: cm.buttonBMove, cost: buttonBCost},
+	}
+	// Test just in case 1 button press completes any problems.
+	for i := range frontier {
+		if frontier[i].currentClawPosition == cm.prizeLocation {
+			viableSolutions = append(viableSolutions, frontier[i])
+		}
+	}
+
+	// Do actual BFS.
+	for remainingDepth > 0 {
+		newFrontier := make([]clawMachineMoveChain, 0)
+		for _, chain := range frontier {
+			aPress := clawMachineMoveChain{
+				aPressCount:         chain.aPressCount + 1,
+				bPressCount:         chain.bPressCount,
+				currentClawPosition: chain.currentClawPosition.add(cm.buttonAMove),
+				cost:                chain.cost + buttonACost,
+			}
+			bPress := clawMachineMoveChain{
+				aPressCount:         chain.aPressCount,
+				bPressCount:         chain.bPressCount + 1,
+				currentClawPosition: chain.currentClawPosition.add(cm.buttonBMove),
+				cost:                chain.cost + buttonBCost,
+			}
+			newFrontier = append(newFrontier, aPress, bPress)
+		}
+		// Remove equivalent moves.
+		newFrontier = lo.UniqBy(newFrontier, func(chain clawMachineMoveChain) presses {
+			return presses{chain.aPressCount, chain.bPressCount}
+		})
+		for _, chain := range newFrontier {
+			if chain.currentClawPosition == cm.prizeLocation {
+				viableSolutions = append(viableSolutions, chain)
+			}
+		}
+		frontier = newFrontier
+		remainingDepth -= 1
+	}
+	return viableSolutions
+}
+
+func bigMul(a, b int) *big.Int {
+	return new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(b)))
+}
+
+func canReachGoal(aMove, bMove, goal coordinate) (int, int, error) {
+	// Apply Cramer's rule. The products are worked out with math/big, as once
+	// the prize is offset for part 2 they can overflow an int (and lose
+	// precision well before that as float64s).
+	determinant := new(big.Int).Sub(bigMul(aMove.x, bMove.y), bigMul(aMove.y, bMove.x))
+
+	if determinant.Sign() == 0 {
+		// Parallel buttons could reach the prize in more than one way, which
+		// the puzzle never asks for.
+		return -1, -1, fmt.Errorf(
+			"buttons A (%d,%d) and B (%d,%d) move in parallel, so the presses needed aren't unique",
+			aMove.x, aMove.y, bMove.x, bMove.y,
+		)
+	}
+
+	aNumerator := new(big.Int).Sub(bigMul(goal.x, bMove.y), bigMul(goal.y, bMove.x))
+	bNumerator := new(big.Int).Sub(bigMul(goal.y, aMove.x), bigMul(goal.x, aMove.y))
+	aPressesNeeded, aRemainder := new(big.Int).QuoRem(aNumerator, determinant, new(big.Int))
+	bPressesNeeded, bRemainder := new(big.Int).QuoRem(bNumerator, determinant, new(big.Int))
+
+	// Negative checks probably not needed here, but it won't hurt.
+	if aRemainder.Sign() != 0 || aPressesNeeded.Sign() < 0 {
+		return -1, -1, nil
+	}
+	if bRemainder.Sign() != 0 || bPressesNeeded.Sign() < 0 {
+		return -1, -1, nil
+	}
+	if !aPressesNeeded.IsInt64() || !bPressesNeeded.IsInt64() {
+		return -1, -1, fmt.Errorf(
+			"%w: reaching the prize needs %v A presses and %v B presses",
+			checked.ErrOverflow,
+			aPressesNeeded,
+			bPressesNeeded,
+		)
+	}
+	// There is some combination of a and b that reach goal.
+	return int(aPressesNeeded.Int64()), int(bPressesNeeded.Int64()), nil
+}
+
+func (cm *clawMachine) betterSearch() (*clawMachineMoveChain, error) {
+	//Filter if we can even reach the solution.
+	a, b, err := canReachGoal(cm.buttonAMove, cm.buttonBMove, cm.prizeLocation)
+	if err != nil {
+		return nil, err
+	}
+	if a == -1 || b == -1 {
+		return nil, nil
+	}
+
+	aCost, aOk := checked.Mul(buttonACost, a)
+	bCost, bOk := checked.Mul(buttonBCost, b)
+	cost, costOk := checked.Add(aCost, bCost)
+	if !aOk || !bOk || !costOk {
+		return nil, fmt.Errorf("%w: cost of %d A presses and %d B presses", checked.ErrOverflow, a, b)
+	}
+
+	// Solutions are unique, why?
+	solutionCandidate := clawMachineMoveChain{
+		aPressCount:         a,
+		bPressCount:         b,
+		currentClawPosition: cm.prizeLocation,
+		cost:                cost,
+	}
+
+	return &solutionCandidate, nil
+}
+
+func parseInput(input string) ([]clawMachine, error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+
+	nextExpectedLine := buttonALine
+	var buttonAMove coordinate
+	var buttonBMove coordinate
+	var prizeLocation coordinate
+	clawMachines := make([]clawMachine, 0)
+	for scanner.Scan() {
+
+		if scanner.Text() == "" {
+			continue
+		}
+		x, y, lineType, err := handleLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		if lineType != nextExpectedLine {
+			return nil, fmt.Errorf("line type not as expected! Expected %v, got %v", nextExpectedLine, lineType)
+		}
+		c := coordinate{
+			x: x,
+			y: y,
+		}
+		if nextExpectedLine == buttonALine {
+			buttonAMove = c
+		}
+		if nextExpectedLine == buttonBLine {
+			buttonBMove = c
+		}
+		if nextExpectedLine == prizeLine {
+			prizeLocation = c
+			clawMachines = append(clawMachines, clawMachine{
+				buttonAMove:   buttonAMove,
+				buttonBMove:   buttonBMove,
+				prizeLocation: prizeLocation,
+			})
+		}
+		nextExpectedLine += 1
+		if nextExpectedLine > prizeLine {
+			nextExpectedLine = buttonALine
+		}
+	}
+	if nextExpectedLine != buttonALine {
+		return nil, fmt.Errorf("input ends part way through a claw machine, expected %v next", nextExpectedLine)
+	}
+	return clawMachines, nil
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	clawMachines, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	minCost := 0
+	for _, cm := range clawMachines {
+		solutions := cm.bruteForceSolutions(200)
+		if len(solutions) > 0 {
+			localMin := lo.MinBy(solutions, func(a clawMachineMoveChain, b clawMachineMoveChain) bool {
+				return a.cost < b.cost
+			})
+			minCost += localMin.cost
+		}
+	}
+	return strconv.Itoa(minCost), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	clawMachines, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	harderGames := make([]clawMachine, len(clawMachines))
+	for i, cm := range clawMachines {
+		x, xOk := checked.Add(cm.prizeLocation.x, prizeOffset)
+		y, yOk := checked.Add(cm.prizeLocation.y, prizeOffset)
+		if !xOk || !yOk {
+			return "", fmt.Errorf("%w: offsetting prize at %d, %d", checked.ErrOverflow, cm.prizeLocation.x, cm.prizeLocation.y)
+		}
+		harderGames[i] = clawMachine{
+			cm.buttonAMove,
+			cm.buttonBMove,
+			coordinate{x: x, y: y},
+		}
+	}
+	minCost := 0
+	for _, cm := range harderGames {
+		solution, err := cm.betterSearch()
+		if err != nil {
+			return "", err
+		}
+		if solution == nil {
+			continue
+		}
+		var ok bool
+		minCost, ok = checked.Add(minCost, solution.cost)
+		if !ok {
+			return "", fmt.Errorf("%w: total cost of all prizes", checked.ErrOverflow)
+		}
+	}
+	return strconv.Itoa(minCost), nil
+}
@@ -0,0 +1,482 @@
+// Package day15 solves day 15, Warehouse Woes.
+package day15
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/samber/lo"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type robotMove int
+
+const (
+	north robotMove = iota
+	east
+	south
+	west
+)
+
+func robotMoveFromString(s string) (robotMove, error) {
+	switch s {
+	case "^":
+		return north, nil
+	case ">":
+		return east, nil
+	case "v":
+		return south, nil
+	case "<":
+		return west, nil
+	}
+	return north, fmt.Errorf("invalid robot move: %s", s)
+}
+
+type gameSpace int
+
+const (
+	blank gameSpace = iota
+	robot
+	box
+	leftSideOfBox
+	rightSideOfBox
+	wall
+)
+
+func gameSpaceFromString(s string) (gameSpace, error) {
+	// We don't parse wide boxes here, as we generate wide maps rather than reading them.
+	switch s {
+	case ".":
+		return blank, nil
+	case "@":
+		return robot, nil
+	case "O":
+		return box, nil
+	case "#":
+		return wall, nil
+	}
+	return blank, fmt.Errorf("invalid game space: %q", s)
+}
+
+func (g gameSpace) String() string {
+	switch g {
+	case blank:
+		return "."
+	case robot:
+		return "@"
+	case box:
+		return "O"
+	case leftSideOfBox:
+		return "["
+	case rightSideOfBox:
+		return "]"
+	case wall:
+		return "#"
+	}
+	return fmt.Sprintf("gameSpace(%d)", int(g))
+}
+
+type coordinate struct {
+	row int
+	col int
+}
+
+// translation is the change in coordinate of one step in the direction of
+// move.
+func translation(move robotMove) (coordinate, error) {
+	switch move {
+	case north:
+		return coordinate{row: -1, col: 0}, nil
+	case east:
+		return coordinate{row: 0, col: 1}, nil
+	case south:
+		return coordinate{row: 1, col: 0}, nil
+	case west:
+		return coordinate{row: 0, col: -1}, nil
+	}
+	return coordinate{}, fmt.Errorf("invalid game move: %d", move)
+}
+
+func (c *coordinate) applyTranslation(t coordinate) coordinate {
+	return coordinate{row: c.row + t.row, col: c.col + t.col}
+}
+
+var errNoMovesLeft = errors.New("no robot moves left")
+
+type gameMap struct {
+	rawMap        [][]gameSpace
+	robotLocation coordinate
+	robotMoves    []robotMove
+	nextMoveIndex int
+}
+
+func newGameMap(rawMap [][]gameSpace, robotMoves []robotMove) (gameMap, error) {
+	for row := range rawMap {
+		for col := range rawMap[row] {
+			if rawMap[row][col] == robot {
+				return gameMap{
+					rawMap:        rawMap,
+					robotLocation: coordinate{row, col},
+					robotMoves:    robotMoves,
+					nextMoveIndex: 0,
+				}, nil
+			}
+		}
+	}
+	return gameMap{}, errors.New("couldn't find robot")
+}
+
+// iterate was written for part 1. iterateMachTwo supersedes this method, but
+// iterate is kept around so we can use it to compare outcomes.
+func (gm *gameMap) iterate() error {
+	if gm.nextMoveIndex >= len(gm.robotMoves) {
+		return errNoMovesLeft
+	}
+	nextMove := gm.robotMoves[gm.nextMoveIndex]
+
+	// No matter what we increment the move.
+	gm.nextMoveIndex += 1
+
+	step, err := translation(nextMove)
+	if err != nil {
+		return err
+	}
+	candidateLocation := gm.robotLocation.applyTranslation(step)
+	if gm.rawMap[candidateLocation.row][candidateLocation.col] == wall {
+		// Robot bumps into a wall, nothing happens.
+		return nil
+	}
+
+	if gm.rawMap[candidateLocation.row][candidateLocation.col] == blank {
+		// Robot moves into a blank space.
+		gm.rawMap[candidateLocation.row][candidateLocation.col] = robot
+		gm.rawMap[gm.robotLocation.row][gm.robotLocation.col] = blank
+		gm.robotLocation = candidateLocation
+		return nil
+	}
+
+	if gm.rawMap[candidateLocation.row][candidateLocation.col] != box {
+		return fmt.Errorf(
+			"unexpected game state (logic bug?), robot at %d,%d must be pushing a box but found %s",
+			gm.robotLocation.row, gm.robotLocation.col, gm.rawMap[candidateLocation.row][candidateLocation.col],
+		)
+	}
+
+	// We're pushing a box.
+	boxCount := 1
+	wallInWay := false
+	nextCoordiates := candidateLocation
+	for {
+		// Check if we're pushing more than 1 box, or hitting a wall.
+		nextCoordiates = nextCoordiates.applyTranslation(step)
+		if gm.rawMap[nextCoordiates.row][nextCoordiates.col] == wall {
+			wallInWay = true
+			break
+		}
+		if gm.rawMap[nextCoordiates.row][nextCoordiates.col] == blank {
+			break
+		}
+		if gm.rawMap[nextCoordiates.row][nextCoordiates.col] != box {
+			return fmt.Errorf(
+				"unexpected game state (logic bug?), expected a box at %d,%d but found %s",
+				nextCoordiates.row, nextCoordiates.col, gm.rawMap[nextCoordiates.row][nextCoordiates.col],
+			)
+		}
+		boxCount += 1
+	}
+
+	if wallInWay {
+		// Can't push, because we're blocked by a wall!
+		return nil
+	}
+
+	// Move robot.
+	gm.rawMap[candidateLocation.row][candidateLocation.col] = robot
+	gm.rawMap[gm.robotLocation.row][gm.robotLocation.col] = blank
+	gm.robotLocation = candidateLocation
+	// Move all boxes.
+	boxLocation := candidateLocation.applyTranslation(step)
+	for range boxCount {
+		gm.rawMap[boxLocation.row][boxLocation.col] = box
+		boxLocation = boxLocation.applyTranslation(step)
+	}
+	return nil
+}
+
+// Iterate version for part 2 that handles push groups.
+func (gm *gameMap) iterateMachTwo() error {
+	if gm.nextMoveIndex >= len(gm.robotMoves) {
+		return errNoMovesLeft
+	}
+	nextMove := gm.robotMoves[gm.nextMoveIndex]
+
+	// No matter what we increment the move.
+	gm.nextMoveIndex += 1
+
+	pg, err := newPushGroup(nextMove, gm)
+	if err != nil {
+		return err
+	}
+	if !pg.canPush() {
+		return nil
+	}
+	pg.push()
+	return nil
+}
+
+func (gm *gameMap) doAllMoves() error {
+	for gm.nextMoveIndex < len(gm.robotMoves) {
+		if err := gm.iterate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gm *gameMap) doAllMovesMachTwo() error {
+	for gm.nextMoveIndex < len(gm.robotMoves) {
+		if err := gm.iterateMachTwo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gm *gameMap) gpsScore() int {
+	score := 0
+	for row := range gm.rawMap {
+		for col := range gm.rawMap[row] {
+			if gm.rawMap[row][col] == box {
+				score += 100*row + col
+			} else if gm.rawMap[row][col] == leftSideOfBox {
+				score += 100*row + col
+			}
+		}
+	}
+	return score
+}
+
+func (gm *gameMap) makeWideMap() (gameMap, error) {
+	wideRawMap := make([][]gameSpace, len(gm.rawMap))
+	for row := range gm.rawMap {
+		wideRawMap[row] = make([]gameSpace, len(gm.rawMap[row])*2)
+		for col, gs := range gm.rawMap[row] {
+			wideCol := col * 2
+			switch gs {
+			case blank:
+				wideRawMap[row][wideCol] = blank
+				wideRawMap[row][wideCol+1] = blank
+			case robot:
+				wideRawMap[row][wideCol] = robot
+				wideRawMap[row][wideCol+1] = blank
+			case box:
+				wideRawMap[row][wideCol] = leftSideOfBox
+				wideRawMap[row][wideCol+1] = rightSideOfBox
+			case wall:
+				wideRawMap[row][wideCol] = wall
+				wideRawMap[row][wideCol+1] = wall
+			default:
+				return gameMap{}, fmt.Errorf(
+					"unexpected game state (logic bug?), found %s at %d,%d. Are you trying to widen an already wide map?",
+					gs, row, col,
+				)
+			}
+		}
+	}
+	for row := range wideRawMap {
+		for col := range wideRawMap[row] {
+			if wideRawMap[row][col] == robot {
+				return gameMap{
+					rawMap:        wideRawMap,
+					robotLocation: coordinate{row, col},
+					robotMoves:    slices.Clone(gm.robotMoves),
+					nextMoveIndex: gm.nextMoveIndex,
+				}, nil
+			}
+		}
+	}
+	return gameMap{}, errors.New("failed to find robot on wide map")
+}
+
+func (gm *gameMap) print() {
+	for row := range gm.rawMap {
+		for col := range gm.rawMap[row] {
+			print(gm.rawMap[row][col].String())
+		}
+		println()
+	}
+}
+
+type pushGroup struct {
+	step             coordinate
+	startCoordinates []coordinate
+	gm               *gameMap
+}
+
+func newPushGroup(
+	direction robotMove,
+	gm *gameMap,
+) (pushGroup, error) {
+	step, err := translation(direction)
+	if err != nil {
+		return pushGroup{}, err
+	}
+	startCoordinates := make([]coordinate, 0)
+
+	frontier := []coordinate{gm.robotLocation}
+	seenCoordinates := make(map[coordinate]struct{})
+	for len(frontier) > 0 {
+		nextFrontier := make([]coordinate, 0)
+		for _, c := range frontier {
+			if _, seen := seenCoordinates[c]; seen {
+				continue
+			}
+			startCoordinates = append(startCoordinates, c)
+			seenCoordinates[c] = struct{}{}
+			nextCoordinate := c.applyTranslation(step)
+			if gm.rawMap[nextCoordinate.row][nextCoordinate.col] == box {
+				nextFrontier = append(nextFrontier, nextCoordinate)
+			} else if gm.rawMap[nextCoordinate.row][nextCoordinate.col] == leftSideOfBox {
+				nextFrontier = append(nextFrontier, nextCoordinate)
+				nextFrontier = append(nextFrontier, coordinate{row: nextCoordinate.row, col: nextCoordinate.col + 1})
+			} else if gm.rawMap[nextCoordinate.row][nextCoordinate.col] == rightSideOfBox {
+				nextFrontier = append(nextFrontier, nextCoordinate)
+				nextFrontier = append(nextFrontier, coordinate{row: nextCoordinate.row, col: nextCoordinate.col - 1})
+			}
+		}
+		frontier = nextFrontier
+	}
+
+	return pushGroup{
+		step:             step,
+		startCoordinates: startCoordinates,
+		gm:               gm,
+	}, nil
+}
+
+func (pg *pushGroup) canPush() bool {
+	pushedCoordinates := make([]coordinate, len(pg.startCoordinates))
+	for i, c := range pg.startCoordinates {
+		nextCoordinate := c.applyTranslation(pg.step)
+		pushedCoordinates[i] = nextCoordinate
+	}
+	for _, c := range pushedCoordinates {
+		if pg.gm.rawMap[c.row][c.col] == wall {
+			return false
+		}
+	}
+	return true
+}
+
+func (pg *pushGroup) push() {
+	// Figure out what coordinates will look like following the push.
+	coordinateToNewValue := make(map[coordinate]gameSpace)
+	for _, c := range pg.startCoordinates {
+		nextCoordinate := c.applyTranslation(pg.step)
+		coordinateToNewValue[nextCoordinate] = pg.gm.rawMap[c.row][c.col]
+	}
+	// Do the push.
+	var robotLocation coordinate
+	for c, gs := range coordinateToNewValue {
+		pg.gm.rawMap[c.row][c.col] = gs
+		if gs == robot {
+			robotLocation = c
+		}
+	}
+	pg.gm.robotLocation = robotLocation
+	// Tidy up coordinates following the push.
+	for _, c := range pg.startCoordinates {
+		_, wasPushedInto := coordinateToNewValue[c]
+		if !wasPushedInto {
+			// If the coordinate wasn't pushed into, it becomes blank.
+			pg.gm.rawMap[c.row][c.col] = blank
+		}
+	}
+}
+
+func handleMapLine(line string) ([]gameSpace, error) {
+	chars := lo.ChunkString(line, 1)
+	mapLine := make([]gameSpace, len(chars))
+	for i, char := range chars {
+		var err error
+		mapLine[i], err = gameSpaceFromString(char)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mapLine, nil
+}
+
+func handleRobotMoveLine(line string) ([]robotMove, error) {
+	chars := lo.ChunkString(line, 1)
+	moves := make([]robotMove, len(chars))
+	for i, char := range chars {
+		var err error
+		moves[i], err = robotMoveFromString(char)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return moves, nil
+}
+
+func parseInput(input string) (gameMap, error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+
+	handlingMap := true
+	rawMap := make([][]gameSpace, 0)
+	robotMoves := make([]robotMove, 0)
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			handlingMap = false
+			continue
+		}
+		if handlingMap {
+			mapLine, err := handleMapLine(scanner.Text())
+			if err != nil {
+				return gameMap{}, err
+			}
+			rawMap = append(rawMap, mapLine)
+		} else {
+			moves, err := handleRobotMoveLine(scanner.Text())
+			if err != nil {
+				return gameMap{}, err
+			}
+			robotMoves = append(robotMoves, moves...)
+		}
+	}
+
+	return newGameMap(rawMap, robotMoves)
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	gm, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	if err := gm.doAllMovesMachTwo(); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(gm.gpsScore()), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	gm, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	wideGm, err := gm.makeWideMap()
+	if err != nil {
+		return "", err
+	}
+	if err := wideGm.doAllMovesMachTwo(); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(wideGm.gpsScore()), nil
+}
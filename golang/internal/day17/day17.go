@@ -0,0 +1,402 @@
+// Package day17 solves day 17, Chronospatial Computer.
+package day17
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"advent_of_code_2024/internal/checked"
+)
+
+var registerLineRegex = regexp.MustCompile(`^Register ([ABC]): (\d+)$`)
+
+func handleRegisterLine(line string) (string, int, error) {
+	matches := registerLineRegex.FindStringSubmatch(line)
+	if len(matches) != 3 {
+		return "", 0, fmt.Errorf("invalid register line %q", line)
+	}
+
+	integer, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return "", 0, err
+	}
+
+	return matches[1], integer, nil
+}
+
+func handleProgramLine(line string) ([]int, error) {
+	trimmedLine, found := strings.CutPrefix(line, "Program: ")
+	if !found {
+		return nil, fmt.Errorf("invalid program line %q", line)
+	}
+	numStrings := strings.Split(trimmedLine, ",")
+
+	nums := make([]int, len(numStrings))
+	for i, numString := range numStrings {
+		num, err := strconv.Atoi(numString)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert program arg %v", numString)
+		}
+		nums[i] = num
+	}
+
+	return nums, nil
+}
+
+type computer struct {
+	registerA          int
+	registerB          int
+	registerC          int
+	program            []int
+	instructionPointer int
+	outputBuffer       []int
+}
+
+func (c *computer) print() {
+	println("A: ", c.registerA)
+	println("B: ", c.registerB)
+	println("C: ", c.registerC)
+	print("Program: ")
+	for _, i := range c.program {
+		print(i, " ")
+	}
+	println()
+	println("InstructionPointer: ", c.instructionPointer)
+}
+
+func (c *computer) decodeComboOperand(operand int) int {
+	switch operand {
+	case 1:
+		fallthrough
+	case 2:
+		fallthrough
+	case 3:
+		return operand
+	case 4:
+		return c.registerA
+	case 5:
+		return c.registerB
+	case 6:
+		return c.registerC
+	default:
+		panic(fmt.Sprintf("Invalid operand: %d", operand))
+	}
+}
+
+// divideByPowerOfTwo returns numerator / 2^exponent. Building 2^exponent
+// overflows an int once exponent reaches 63 (and takes exponent steps to build),
+// so shift instead. Registers never go negative, so the shift matches the
+// division exactly, including giving 0 once the exponent passes the int width.
+func divideByPowerOfTwo(numerator, exponent int) int {
+	return numerator >> exponent
+}
+
+// opcode 0.
+func (c *computer) adv(operand int) {
+	operandValue := c.decodeComboOperand(operand)
+	c.registerA = divideByPowerOfTwo(c.registerA, operandValue)
+}
+
+// opcode 1.
+func (c *computer) bxl(operand int) {
+	c.registerB ^= operand
+}
+
+// opcode 2.
+func (c *computer) bst(operand int) {
+	operandValue := c.decodeComboOperand(operand)
+	c.registerB = operandValue % 8
+}
+
+// opcode 3.
+func (c *computer) jnz(operand int) bool {
+	if c.registerA == 0 {
+		return true
+	}
+	c.instructionPointer = operand
+	return false
+}
+
+// opcode 4.
+func (c *computer) bxc(_ int) {
+	c.registerB ^= c.registerC
+}
+
+// opcode 5.
+func (c *computer) out(operand int) {
+	operandValue := c.decodeComboOperand(operand)
+	c.outputBuffer = append(c.outputBuffer, operandValue%8)
+}
+
+// opcode 6.
+func (c *computer) bdv(operand int) {
+	operandValue := c.decodeComboOperand(operand)
+	c.registerB = divideByPowerOfTwo(c.registerA, operandValue)
+}
+
+// opcode 7.
+func (c *computer) cdv(operand int) {
+	operandValue := c.decodeComboOperand(operand)
+	c.registerC = divideByPowerOfTwo(c.registerA, operandValue)
+}
+
+func (c *computer) runInstruction() {
+	opcode := c.program[c.instructionPointer]
+	operand := c.program[c.instructionPointer+1]
+	increaseInstructionPointer := true
+	switch opcode {
+	case 0:
+		c.adv(operand)
+	case 1:
+		c.bxl(operand)
+	case 2:
+		c.bst(operand)
+	case 3:
+		increaseInstructionPointer = c.jnz(operand)
+	case 4:
+		c.bxc(operand)
+	case 5:
+		c.out(operand)
+	case 6:
+		c.bdv(operand)
+	case 7:
+		c.cdv(operand)
+	default:
+		panic(fmt.Sprintf("Invalid instruction opcode: %d", opcode))
+	}
+
+	if increaseInstructionPointer {
+		c.instructionPointer += 2
+	}
+}
+
+func (c *computer) runProgram() string {
+	for c.instructionPointer < len(c.program) {
+		c.runInstruction()
+	}
+	outputItems := lo.Map(c.outputBuffer, func(item int, _ int) string {
+		return fmt.Sprintf("%d", item)
+	})
+	return strings.Join(outputItems, ",")
+}
+
+func (c *computer) checkIfProgramPrintsSelf() bool {
+	checkedOutputTo := 0
+	for c.instructionPointer < len(c.program) {
+		c.runInstruction()
+		for i := checkedOutputTo; i < len(c.outputBuffer); i++ {
+			if c.outputBuffer[i] != c.program[i] {
+				return false
+			}
+			checkedOutputTo = i
+		}
+	}
+	if len(c.outputBuffer) != len(c.program) {
+		return false
+	}
+	return true
+}
+
+type opcodeAndOperand struct {
+	opcode  int
+	operand int
+}
+
+type registerContentType int
+
+const (
+	literal registerContentType = iota
+	literalModEight
+	unknown
+)
+
+func (c *computer) findRegisterAThatPrintsProgram() (int, error) {
+	opcodesAndOperands := make([]opcodeAndOperand, len(c.program)/2)
+	for i := range opcodesAndOperands {
+		opcodesAndOperands[i] = opcodeAndOperand{
+			opcode:  c.program[i*2],
+			operand: c.program[i*2+1],
+		}
+	}
+
+	count := lo.CountBy(opcodesAndOperands, func(oao opcodeAndOperand) bool {
+		return oao.opcode == 0
+	})
+	if count != 1 {
+		return 0, errors.New("expected 1 adiv")
+	}
+	adivOpAndOperand, _, found := lo.FindIndexOf(opcodesAndOperands, func(oao opcodeAndOperand) bool {
+		return oao.opcode == 0
+	})
+	if !found {
+		return 0, errors.New("must find adiv op")
+	}
+	if adivOpAndOperand.operand > 3 {
+		return 0, errors.New("don't expect register operands")
+	}
+	adDivDenominator := 1 << adivOpAndOperand.operand
+
+	reversedOpcodesAndOperands := lo.Reverse(opcodesAndOperands)
+
+	if reversedOpcodesAndOperands[0].opcode != 3 {
+		return 0, fmt.Errorf("invalid opcode, expected jnz as last op, got: %d", reversedOpcodesAndOperands[0].opcode)
+	}
+	if reversedOpcodesAndOperands[0].operand != 0 {
+		return 0, fmt.Errorf(
+			"expect jnz to jump to instruction 0, but jumps to %d, our logic doesn't handle that",
+			reversedOpcodesAndOperands[0].operand,
+		)
+	}
+	for _, oao := range reversedOpcodesAndOperands[1:] {
+		if oao.opcode == 3 {
+			return 0, errors.New("expected only jnz in program to be at end")
+		}
+	}
+
+	// Index 0 -> solutions for sub-problem with just last elem of program.
+	// Index 1 -> solutions for sub-problem with last 2 elems of program.
+	// ...
+	// Index len(c.program) - 1 -> solution for whole problem.
+	solutionsForSubproblems := make([][]int, len(c.program))
+	var lastSolutions []int
+	for i := range solutionsForSubproblems {
+		var candidateSolutions []int
+		if lastSolutions == nil {
+			candidateSolutions = make([]int, 0)
+			for i := 1; i < adDivDenominator; i++ {
+				candidateSolutions = append(candidateSolutions, i)
+			}
+		} else {
+			for _, lastSolution := range lastSolutions {
+				// For every solution in our last set, we add a range of solutions:
+				// - prevSolution * aDivDenominator at the bottom end.
+				// - prevSolution * aDivDenominator + (aDivDenominator - 1) at the top end.
+				// - and all numbers in between.
+				//
+				// An example of why we do so: if our previous solutions were 2 and 4, with a div of 5 then all the
+				// combinations that could lead to 2 or 4 are.
+				// 10, 11, 12, 13, 14, 20, 21, 22, 23, 24. Because
+				// 10 / 5 = 2, 11 / 5 = 2, 12 / 5 = 2, 13 / 5 = 2, 14 / 5 = 2 and
+				// 20 / 5 = 4, 21 / 5 = 4, 22 / 5 = 4, 23 / 5 = 4, 24 / 5 = 4.
+				//
+				// The calculations here are the same as the long form example, and derive all potential candidates
+				// that would lead to the solutions the next previous solutions.
+				base, ok := checked.Mul(lastSolution, adDivDenominator)
+				if !ok {
+					return 0, fmt.Errorf(
+						"%w: register A candidate %d * %d, the program is too long for an int register",
+						checked.ErrOverflow,
+						lastSolution,
+						adDivDenominator,
+					)
+				}
+				for i := range adDivDenominator {
+					candidate, ok := checked.Add(base, i)
+					if !ok {
+						return 0, fmt.Errorf("%w: register A candidate %d + %d", checked.ErrOverflow, base, i)
+					}
+					candidateSolutions = append(candidateSolutions, candidate)
+				}
+			}
+		}
+		actualSolutions := make([]int, 0)
+		subProblemOutputIndex := i + 1
+		subProblemOutput := c.program[len(c.program)-subProblemOutputIndex]
+		for i := range candidateSolutions {
+			c.outputBuffer = make([]int, 0)
+			c.registerA = candidateSolutions[i]
+			c.registerB = 0
+			c.registerC = 0
+			c.instructionPointer = 0
+			for len(c.outputBuffer) == 0 && c.instructionPointer < len(c.program)-2 /* stop before last jump */ {
+				c.runInstruction()
+			}
+			if len(c.outputBuffer) > 0 && c.outputBuffer[0] == subProblemOutput {
+				actualSolutions = append(actualSolutions, candidateSolutions[i])
+			}
+		}
+		solutionsForSubproblems[i] = actualSolutions
+		lastSolutions = actualSolutions
+	}
+	return lo.Min(solutionsForSubproblems[len(solutionsForSubproblems)-1]), nil
+}
+
+func newComputer(registerA int, registerB int, registerC int, program []int) computer {
+	return computer{
+		registerA:          registerA,
+		registerB:          registerB,
+		registerC:          registerC,
+		program:            program,
+		instructionPointer: 0,
+		outputBuffer:       make([]int, 0),
+	}
+}
+
+func parseInput(input string) (computer, error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+
+	handlingRegisters := true
+	registerCount := 0
+	var registerA int
+	var registerB int
+	var registerC int
+	var program []int
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			handlingRegisters = false
+			continue
+		}
+		if handlingRegisters {
+			_, registerValue, err := handleRegisterLine(scanner.Text())
+			if err != nil {
+				return computer{}, err
+			}
+			if registerCount == 0 {
+				registerA = registerValue
+			} else if registerCount == 1 {
+				registerB = registerValue
+			} else if registerCount == 2 {
+				registerC = registerValue
+			} else {
+				return computer{}, errors.New("invalid register count")
+			}
+			registerCount += 1
+			continue
+		}
+		var err error
+		program, err = handleProgramLine(scanner.Text())
+		if err != nil {
+			return computer{}, err
+		}
+	}
+
+	return newComputer(registerA, registerB, registerC, program), nil
+}
+
+type Solver struct{}
+
+func (Solver) PartOne(input string) (string, error) {
+	comp, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	return comp.runProgram(), nil
+}
+
+func (Solver) PartTwo(input string) (string, error) {
+	comp, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	registerA, err := comp.findRegisterAThatPrintsProgram()
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(registerA), nil
+}
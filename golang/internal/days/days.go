@@ -0,0 +1,65 @@
+// Package days maps each day number to its solver.
+package days
+
+import (
+	"advent_of_code_2024/internal/day01"
+	"advent_of_code_2024/internal/day02"
+	"advent_of_code_2024/internal/day03"
+	"advent_of_code_2024/internal/day04"
+	"advent_of_code_2024/internal/day05"
+	"advent_of_code_2024/internal/day06"
+	"advent_of_code_2024/internal/day07"
+	"advent_of_code_2024/internal/day08"
+	"advent_of_code_2024/internal/day09"
+	"advent_of_code_2024/internal/day10"
+	"advent_of_code_2024/internal/day11"
+	"advent_of_code_2024/internal/day12"
+	"advent_of_code_2024/internal/day13"
+	"advent_of_code_2024/internal/day14"
+	"advent_of_code_2024/internal/day15"
+	"advent_of_code_2024/internal/day16"
+	"advent_of_code_2024/internal/day17"
+	"advent_of_code_2024/internal/day18"
+	"advent_of_code_2024/internal/day19"
+	"advent_of_code_2024/internal/day20"
+	"advent_of_code_2024/internal/day21"
+	"advent_of_code_2024/internal/day22"
+	"advent_of_code_2024/internal/day23"
+	"advent_of_code_2024/internal/day24"
+	"advent_of_code_2024/internal/day25"
+	"advent_of_code_2024/internal/solver"
+)
+
+var solvers = map[int]solver.Solver{
+	1:  day01.Solver{},
+	2:  day02.Solver{},
+	3:  day03.Solver{},
+	4:  day04.Solver{},
+	5:  day05.Solver{},
+	6:  day06.Solver{},
+	7:  day07.Solver{},
+	8:  day08.Solver{},
+	9:  day09.Solver{},
+	10: day10.Solver{},
+	11: day11.Solver{},
+	12: day12.Solver{},
+	13: day13.Solver{},
+	14: day14.Solver{},
+	15: day15.Solver{},
+	16: day16.Solver{},
+	17: day17.Solver{},
+	18: day18.Solver{},
+	19: day19.Solver{},
+	20: day20.Solver{},
+	21: day21.Solver{},
+	22: day22.Solver{},
+	23: day23.Solver{},
+	24: day24.Solver{},
+	25: day25.Solver{},
+}
+
+// Get returns the solver for day, if there is one.
+func Get(day int) (solver.Solver, bool) {
+	s, ok := solvers[day]
+	return s, ok
+}
@@ -0,0 +1,52 @@
+package days
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"advent_of_code_2024/internal/solver"
+)
+
+// examples lists the puzzle examples checked in alongside each day, with
+// their answers from the puzzle text.
+var examples = []struct {
+	day     int
+	path    string
+	partOne string
+	partTwo string
+}{
+	{day: 1, path: "../day01/testdata/example", partOne: "11", partTwo: "31"},
+	{day: 2, path: "../day02/testdata/example", partOne: "2", partTwo: "4"},
+	{day: 3, path: "../day03/testdata/example", partOne: "161", partTwo: "161"},
+	{day: 3, path: "../day03/testdata/example2", partOne: "161", partTwo: "48"},
+	{day: 4, path: "../day04/testdata/example", partOne: "18", partTwo: "9"},
+	{day: 5, path: "../day05/testdata/example", partOne: "143", partTwo: "123"},
+	{day: 6, path: "../day06/testdata/example", partOne: "41", partTwo: "6"},
+	{day: 7, path: "../day07/testdata/example", partOne: "3749", partTwo: "11387"},
+	{day: 8, path: "../day08/testdata/example", partOne: "14", partTwo: "34"},
+}
+
+func TestExamples(t *testing.T) {
+	for _, tc := range examples {
+		data, err := os.ReadFile(tc.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, ok := Get(tc.day)
+		if !ok {
+			t.Fatalf("no solver for day %d", tc.day)
+		}
+		for part, want := range []string{tc.partOne, tc.partTwo} {
+			t.Run(fmt.Sprintf("%s/part%d", tc.path, part+1), func(t *testing.T) {
+				got, err := solver.Solve(s, part+1, string(data))
+				if err != nil {
+					t.Fatalf("day %d part %d: %v", tc.day, part+1, err)
+				}
+				if got != want {
+					t.Errorf("day %d part %d = %q, want %q", tc.day, part+1, got, want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Package lint checks puzzle inputs against a per-day schema so that bad
+// input is reported up front, rather than showing up as an odd failure deep
+// inside a solver.
+package lint
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Violation is a single problem found in an input.
+type Violation struct {
+	Line    int
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("line %d: %s", v.Line, v.Message)
+}
+
+type line struct {
+	number int
+	text   string
+}
+
+// rule checks one blank-line separated section of input and returns every
+// violation it finds.
+type rule func(section []line) []Violation
+
+type schema struct {
+	// sections are applied in order to the sections of the input.
+	sections []rule
+	// repeatLast applies the last section rule to any further sections, for
+	// inputs made of repeated blocks (claw machines, locks and keys).
+	repeatLast bool
+	// check runs once over all sections, for constraints between sections.
+	check func(sections [][]line) []Violation
+}
+
+// Lint validates input against the schema for day. It reports every
+// violation found rather than stopping at the first one.
+func Lint(day int, input string) ([]Violation, error) {
+	s, ok := schemas[day]
+	if !ok {
+		return nil, fmt.Errorf("no lint schema for day %d", day)
+	}
+
+	sections, violations := splitSections(input)
+
+	if len(sections) == 0 {
+		return append(violations, Violation{Line: 1, Message: "input is empty"}), nil
+	}
+
+	if !s.repeatLast && len(sections) > len(s.sections) {
+		for _, extra := range sections[len(s.sections):] {
+			violations = append(violations, Violation{
+				Line:    extra[0].number,
+				Message: fmt.Sprintf("unexpected section, expected %d section(s)", len(s.sections)),
+			})
+		}
+		sections = sections[:len(s.sections)]
+	}
+	if len(sections) < len(s.sections) {
+		last := sections[len(sections)-1]
+		violations = append(violations, Violation{
+			Line: last[len(last)-1].number + 1,
+			Message: fmt.Sprintf(
+				"missing section, expected %d section(s) separated by a blank line, found %d",
+				len(s.sections),
+				len(sections),
+			),
+		})
+	}
+
+	for i, section := range sections {
+		r := s.sections[min(i, len(s.sections)-1)]
+		violations = append(violations, r(section)...)
+	}
+
+	if s.check != nil {
+		violations = append(violations, s.check(sections)...)
+	}
+
+	slices.SortStableFunc(violations, func(a, b Violation) int {
+		return a.Line - b.Line
+	})
+	return violations, nil
+}
+
+// splitSections splits input on blank lines. More than one blank line in a row
+// is reported, as the solvers count every blank line as a section break.
+func splitSections(input string) ([][]line, []Violation) {
+	sections := make([][]line, 0)
+	violations := make([]Violation, 0)
+
+	current := make([]line, 0)
+	blankRun := 0
+	lineNumber := 0
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		lineNumber += 1
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if text == "" {
+			blankRun += 1
+			if len(current) > 0 {
+				sections = append(sections, current)
+				current = make([]line, 0)
+			}
+			continue
+		}
+		if blankRun > 1 && len(sections) > 0 {
+			violations = append(violations, Violation{
+				Line:    lineNumber - blankRun,
+				Message: fmt.Sprintf("%d blank lines in a row, sections are separated by exactly one", blankRun),
+			})
+		}
+		blankRun = 0
+		current = append(current, line{number: lineNumber, text: text})
+	}
+	if len(current) > 0 {
+		sections = append(sections, current)
+	}
+
+	return sections, violations
+}
+
+// matchLines requires every line in a section to match re.
+func matchLines(re *regexp.Regexp, description string) rule {
+	return func(section []line) []Violation {
+		violations := make([]Violation, 0)
+		for _, l := range section {
+			if !re.MatchString(l.text) {
+				violations = append(violations, Violation{
+					Line:    l.number,
+					Message: fmt.Sprintf("expected %s, got %q", description, l.text),
+				})
+			}
+		}
+		return violations
+	}
+}
+
+type expectedLine struct {
+	re          *regexp.Regexp
+	description string
+}
+
+// sequence requires a section to be exactly the given lines, in order.
+func sequence(expected ...expectedLine) rule {
+	return func(section []line) []Violation {
+		violations := make([]Violation, 0)
+		for i, l := range section {
+			if i >= len(expected) {
+				violations = append(violations, Violation{
+					Line:    l.number,
+					Message: fmt.Sprintf("unexpected line, section should have %d line(s)", len(expected)),
+				})
+				continue
+			}
+			if !expected[i].re.MatchString(l.text) {
+				violations = append(violations, Violation{
+					Line:    l.number,
+					Message: fmt.Sprintf("expected %s, got %q", expected[i].description, l.text),
+				})
+			}
+		}
+		for i := len(section); i < len(expected); i++ {
+			violations = append(violations, Violation{
+				Line:    section[len(section)-1].number + 1,
+				Message: fmt.Sprintf("missing %s", expected[i].description),
+			})
+		}
+		return violations
+	}
+}
+
+type uniqueCell struct {
+	chars       string
+	description string
+}
+
+type cellPosition struct {
+	line, col int
+}
+
+type gridSpec struct {
+	// allowed lists every character that may appear in the grid.
+	allowed string
+	// unique lists sets of characters that must appear exactly once between
+	// them, such as any of "^>v<" for a single guard.
+	unique []uniqueCell
+}
+
+// grid requires a section to be a rectangular grid of allowed characters.
+func grid(spec gridSpec) rule {
+	return func(section []line) []Violation {
+		violations := make([]Violation, 0)
+		width := len(section[0].text)
+
+		firstSeen := make([]*cellPosition, len(spec.unique))
+		for _, l := range section {
+			if len(l.text) != width {
+				violations = append(violations, Violation{
+					Line:    l.number,
+					Message: fmt.Sprintf("row is %d wide, expected %d to match the first row", len(l.text), width),
+				})
+			}
+			for col, char := range l.text {
+				if !strings.ContainsRune(spec.allowed, char) {
+					violations = append(violations, Violation{
+						Line:    l.number,
+						Message: fmt.Sprintf("unexpected character %q at column %d", char, col+1),
+					})
+					continue
+				}
+				for i, u := range spec.unique {
+					if !strings.ContainsRune(u.chars, char) {
+						continue
+					}
+					if firstSeen[i] == nil {
+						firstSeen[i] = &cellPosition{line: l.number, col: col + 1}
+						continue
+					}
+					violations = append(violations, Violation{
+						Line: l.number,
+						Message: fmt.Sprintf(
+							"extra %s %q at column %d, first seen on line %d column %d",
+							u.description,
+							char,
+							col+1,
+							firstSeen[i].line,
+							firstSeen[i].col,
+						),
+					})
+				}
+			}
+		}
+
+		for i, u := range spec.unique {
+			if firstSeen[i] == nil {
+				violations = append(violations, Violation{
+					Line:    section[0].number,
+					Message: fmt.Sprintf("no %s found, expected exactly one", u.description),
+				})
+			}
+		}
+		return violations
+	}
+}
+
+// anyText accepts any non-blank line.
+func anyText(_ []line) []Violation {
+	return nil
+}
@@ -0,0 +1,152 @@
+package lint
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name  string
+		day   int
+		input string
+		want  []Violation
+	}{
+		{
+			name:  "valid",
+			day:   5,
+			input: "47|53\n97|13\n\n75,47,61\n",
+			want:  []Violation{},
+		},
+		{
+			name:  "blank line run reported at its first line",
+			day:   5,
+			input: "47|53\n97|13\n75|29\n\n\n75,47,61\n",
+			want:  []Violation{{Line: 4, Message: "2 blank lines in a row, sections are separated by exactly one"}},
+		},
+		{
+			name:  "bad line",
+			day:   5,
+			input: "47|53\n97-13\n\n75,47,61\n",
+			want:  []Violation{{Line: 2, Message: `expected an ordering rule like 47|53, got "97-13"`}},
+		},
+		{
+			name:  "empty",
+			day:   5,
+			input: "",
+			want:  []Violation{{Line: 1, Message: "input is empty"}},
+		},
+		{
+			name:  "several violations in line order",
+			day:   1,
+			input: "3   4\n4 x\n2   5\n1,3\n",
+			want: []Violation{
+				{Line: 2, Message: `expected two numbers separated by whitespace, got "4 x"`},
+				{Line: 4, Message: `expected two numbers separated by whitespace, got "1,3"`},
+			},
+		},
+		{
+			name:  "extra section",
+			day:   1,
+			input: "3   4\n\n4   3\n",
+			want:  []Violation{{Line: 3, Message: "unexpected section, expected 1 section(s)"}},
+		},
+		{
+			name:  "missing section",
+			day:   5,
+			input: "47|53\n97|13\n",
+			want: []Violation{
+				{Line: 3, Message: "missing section, expected 2 section(s) separated by a blank line, found 1"},
+			},
+		},
+		{
+			name:  "blank runs and bad lines together",
+			day:   5,
+			input: "47|53\n\n\n\n75,47,61\n75;47\n",
+			want: []Violation{
+				{Line: 2, Message: "3 blank lines in a row, sections are separated by exactly one"},
+				{Line: 6, Message: `expected comma separated page numbers, got "75;47"`},
+			},
+		},
+		{
+			name:  "ragged grid",
+			day:   4,
+			input: "XMAS\nSAM\nXMAS\n",
+			want:  []Violation{{Line: 2, Message: "row is 3 wide, expected 4 to match the first row"}},
+		},
+		{
+			name:  "two guards and a bad character",
+			day:   6,
+			input: "..#.\n.^..\n..x.\n...<\n",
+			want: []Violation{
+				{Line: 3, Message: `unexpected character 'x' at column 3`},
+				{Line: 4, Message: `extra guard '<' at column 4, first seen on line 2 column 2`},
+			},
+		},
+		{
+			name:  "no guard",
+			day:   6,
+			input: "\n..#.\n....\n",
+			want:  []Violation{{Line: 2, Message: "no guard found, expected exactly one"}},
+		},
+		{
+			name:  "disk map",
+			day:   9,
+			input: "12a4\n5\n",
+			want: []Violation{
+				{Line: 1, Message: `unexpected character 'a' at column 3`},
+				{Line: 1, Message: "disk map has 4 digits, expected an odd count so there is one more file than free space"},
+				{Line: 2, Message: "unexpected line, the disk map is a single line"},
+			},
+		},
+		{
+			name: "claw machines",
+			day:  13,
+			input: "Button A: X+94, Y+34\nButton B: X+22, Y+67\nPrize: X=8400, Y=5400\n\n" +
+				"Button A: X+26, Y+66\nPrize: X=12748, Y=12176\n",
+			want: []Violation{
+				{Line: 6, Message: `expected a Button B line, got "Prize: X=12748, Y=12176"`},
+				{Line: 7, Message: "missing a Prize line"},
+			},
+		},
+		{
+			name:  "robot outside the pattern",
+			day:   14,
+			input: "p=0,4 v=3,-3\np=6,3 v=-1\n",
+			want:  []Violation{{Line: 2, Message: `expected a robot like p=0,4 v=3,-3, got "p=6,3 v=-1"`}},
+		},
+		{
+			name:  "program section too long",
+			day:   17,
+			input: "Register A: 729\nRegister B: 0\nRegister C: 0\n\nProgram: 0,1,5,4,3,0\nProgram: 0\n",
+			want:  []Violation{{Line: 6, Message: "unexpected line, section should have 1 line(s)"}},
+		},
+		{
+			name:  "locks and keys",
+			day:   25,
+			input: "###\n#..\n...\n\n...\n.#.\n#.#\n###\n\n#.\n..\n..\n",
+			want: []Violation{
+				{Line: 5, Message: "schematic is 4 rows tall, expected 3 to match the first"},
+				{Line: 10, Message: "schematic is 2 wide, expected 3 to match the first"},
+				{Line: 10, Message: "schematic must have either its top row (lock) or its bottom row (key) filled with #"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Lint(tt.day, tt.input)
+			if err != nil {
+				t.Fatalf("Lint() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintUnknownDay(t *testing.T) {
+	if _, err := Lint(26, "x"); err == nil {
+		t.Error("Lint() error = nil, want an error for a day without a schema")
+	}
+}
@@ -0,0 +1,193 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	digits       = "0123456789"
+	lowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	upperLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+var schemas = map[int]schema{
+	1: {sections: []rule{
+		matchLines(regexp.MustCompile(`^\d+[ \t]+\d+$`), "two numbers separated by whitespace"),
+	}},
+	2: {sections: []rule{
+		matchLines(regexp.MustCompile(`^\d+( \d+)*$`), "space separated levels"),
+	}},
+	// Corrupted memory can contain anything, so only the section layout is checked.
+	3: {sections: []rule{anyText}},
+	4: {sections: []rule{
+		grid(gridSpec{allowed: "XMAS"}),
+	}},
+	5: {sections: []rule{
+		matchLines(regexp.MustCompile(`^\d+\|\d+$`), "an ordering rule like 47|53"),
+		matchLines(regexp.MustCompile(`^\d+(,\d+)*$`), "comma separated page numbers"),
+	}},
+	6: {sections: []rule{
+		grid(gridSpec{
+			allowed: ".#^>v<",
+			unique:  []uniqueCell{{chars: "^>v<", description: "guard"}},
+		}),
+	}},
+	7: {sections: []rule{
+		matchLines(regexp.MustCompile(`^\d+:( \d+)+$`), "a target, a colon and space separated numbers"),
+	}},
+	8: {sections: []rule{
+		grid(gridSpec{allowed: "." + digits + lowerLetters + upperLetters}),
+	}},
+	9: {sections: []rule{diskMap}},
+	10: {sections: []rule{
+		grid(gridSpec{allowed: "." + digits}),
+	}},
+	11: {sections: []rule{
+		sequence(expectedLine{regexp.MustCompile(`^\d+( \d+)*$`), "space separated stones"}),
+	}},
+	12: {sections: []rule{
+		grid(gridSpec{allowed: upperLetters}),
+	}},
+	13: {
+		sections: []rule{
+			sequence(
+				expectedLine{regexp.MustCompile(`^Button A: X\+\d+, Y\+\d+$`), "a Button A line"},
+				expectedLine{regexp.MustCompile(`^Button B: X\+\d+, Y\+\d+$`), "a Button B line"},
+				expectedLine{regexp.MustCompile(`^Prize: X=\d+, Y=\d+$`), "a Prize line"},
+			),
+		},
+		repeatLast: true,
+	},
+	14: {sections: []rule{
+		matchLines(regexp.MustCompile(`^p=-?\d+,-?\d+ v=-?\d+,-?\d+$`), "a robot like p=0,4 v=3,-3"),
+	}},
+	15: {sections: []rule{
+		grid(gridSpec{
+			allowed: "#.O@",
+			unique:  []uniqueCell{{chars: "@", description: "robot"}},
+		}),
+		matchLines(regexp.MustCompile(`^[<>^v]+$`), "robot moves made of <>^v"),
+	}},
+	16: {sections: []rule{
+		grid(gridSpec{
+			allowed: "#.SE",
+			unique: []uniqueCell{
+				{chars: "S", description: "start tile"},
+				{chars: "E", description: "end tile"},
+			},
+		}),
+	}},
+	17: {sections: []rule{
+		sequence(
+			expectedLine{regexp.MustCompile(`^Register A: \d+$`), "a Register A line"},
+			expectedLine{regexp.MustCompile(`^Register B: \d+$`), "a Register B line"},
+			expectedLine{regexp.MustCompile(`^Register C: \d+$`), "a Register C line"},
+		),
+		sequence(expectedLine{regexp.MustCompile(`^Program: [0-7](,[0-7])*$`), "a Program line of 3-bit numbers"}),
+	}},
+	18: {sections: []rule{
+		matchLines(regexp.MustCompile(`^\d+,\d+$`), "a byte position like 5,4"),
+	}},
+	19: {sections: []rule{
+		sequence(expectedLine{regexp.MustCompile(`^[wubrg]+(, [wubrg]+)*$`), "comma separated towel patterns"}),
+		matchLines(regexp.MustCompile(`^[wubrg]+$`), "a design made of w, u, b, r and g"),
+	}},
+	20: {sections: []rule{
+		grid(gridSpec{
+			allowed: "#.SE",
+			unique: []uniqueCell{
+				{chars: "S", description: "start tile"},
+				{chars: "E", description: "end tile"},
+			},
+		}),
+	}},
+	21: {sections: []rule{
+		matchLines(regexp.MustCompile(`^\d{3}A$`), "a door code like 029A"),
+	}},
+	22: {sections: []rule{
+		matchLines(regexp.MustCompile(`^\d+$`), "a secret number"),
+	}},
+	23: {sections: []rule{
+		matchLines(regexp.MustCompile(`^[a-z]{2}-[a-z]{2}$`), "a connection like kh-tc"),
+	}},
+	24: {sections: []rule{
+		matchLines(regexp.MustCompile(`^\w+: [01]$`), "an initial wire value like x00: 1"),
+		matchLines(regexp.MustCompile(`^\w+ (AND|OR|XOR) \w+ -> \w+$`), "a gate like x00 AND y00 -> z00"),
+	}},
+	25: {
+		sections: []rule{
+			grid(gridSpec{allowed: "#."}),
+		},
+		repeatLast: true,
+		check:      sameSizeSchematics,
+	},
+}
+
+// diskMap checks day09's single line of alternating file and free space
+// sizes, which must start and end with a file.
+func diskMap(section []line) []Violation {
+	violations := make([]Violation, 0)
+	for _, l := range section[1:] {
+		violations = append(violations, Violation{
+			Line:    l.number,
+			Message: "unexpected line, the disk map is a single line",
+		})
+	}
+
+	l := section[0]
+	for col, char := range l.text {
+		if !strings.ContainsRune(digits, char) {
+			violations = append(violations, Violation{
+				Line:    l.number,
+				Message: fmt.Sprintf("unexpected character %q at column %d", char, col+1),
+			})
+		}
+	}
+	if len(l.text)%2 == 0 {
+		violations = append(violations, Violation{
+			Line: l.number,
+			Message: fmt.Sprintf(
+				"disk map has %d digits, expected an odd count so there is one more file than free space",
+				len(l.text),
+			),
+		})
+	}
+	return violations
+}
+
+// sameSizeSchematics checks that every day25 lock and key has the same size
+// as the first, and is either a lock (filled top row) or a key (filled bottom
+// row).
+func sameSizeSchematics(sections [][]line) []Violation {
+	violations := make([]Violation, 0)
+	height := len(sections[0])
+	width := len(sections[0][0].text)
+	for _, section := range sections {
+		if len(section) != height {
+			violations = append(violations, Violation{
+				Line:    section[0].number,
+				Message: fmt.Sprintf("schematic is %d rows tall, expected %d to match the first", len(section), height),
+			})
+		}
+		if len(section[0].text) != width {
+			violations = append(violations, Violation{
+				Line:    section[0].number,
+				Message: fmt.Sprintf("schematic is %d wide, expected %d to match the first", len(section[0].text), width),
+			})
+		}
+
+		top := section[0].text
+		bottom := section[len(section)-1].text
+		isLock := strings.Trim(top, "#") == ""
+		isKey := strings.Trim(bottom, "#") == ""
+		if isLock == isKey {
+			violations = append(violations, Violation{
+				Line:    section[0].number,
+				Message: "schematic must have either its top row (lock) or its bottom row (key) filled with #",
+			})
+		}
+	}
+	return violations
+}
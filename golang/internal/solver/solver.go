@@ -0,0 +1,57 @@
+// Package solver defines the contract every day's puzzle logic implements, so
+// that a day can be solved for any input rather than only its embedded one.
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrNoPart is returned for a part a day doesn't have, such as day 25's part
+// two.
+var ErrNoPart = errors.New("puzzle has no such part")
+
+// Solver solves both parts of a single day's puzzle. Answers are returned as
+// strings as some days answer with text rather than a number.
+type Solver interface {
+	PartOne(input string) (string, error)
+	PartTwo(input string) (string, error)
+}
+
+// Run solves both parts for input and prints the answers.
+func Run(s Solver, input string) {
+	partOne, err := s.PartOne(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(partOne)
+
+	partTwo, err := s.PartTwo(input)
+	if errors.Is(err, ErrNoPart) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(partTwo)
+}
+
+// Solve runs a single part of s. A panic inside the solver is returned as an
+// error, so that one bad input doesn't take down every other solve running
+// alongside it.
+func Solve(s Solver, part int, input string) (answer string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("solver panicked: %v", r)
+		}
+	}()
+
+	switch part {
+	case 1:
+		return s.PartOne(input)
+	case 2:
+		return s.PartTwo(input)
+	}
+	return "", fmt.Errorf("invalid part %d, expected 1 or 2", part)
+}